@@ -0,0 +1,24 @@
+/*
+Command ssha hashes and verifies passwords using the salted SHA-1 "{SSHA}"
+scheme of the github.com/kristinjeanna/crypto/ssha1 package.
+
+Usage:
+
+	ssha hash [-salt-bytes n] [password]
+	ssha verify <stored> [password]
+
+The hash subcommand prints the "{SSHA}" string for the password, using a
+random salt of the specified size (20 bytes by default).
+
+The verify subcommand checks the password against a stored hash and exits
+with status 0 if it matches and 1 if it does not. Usage and decoding errors
+exit with status 2.
+
+If the password is not given as an argument, it is read from standard
+input, so it does not end up in the shell history. A single trailing
+newline is removed:
+
+	printf '%s\n' "$PASSWORD" | ssha hash
+	ssha verify '{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==' < password.txt
+*/
+package main
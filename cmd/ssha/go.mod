@@ -0,0 +1,8 @@
+module github.com/kristinjeanna/crypto/cmd/ssha
+
+go 1.18
+
+require github.com/kristinjeanna/crypto/ssha1 v1.1.0
+
+require github.com/kristinjeanna/crypto v1.1.0 // indirect
+
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kristinjeanna/crypto/ssha1"
+)
+
+// Exit statuses.
+const (
+	exitOK       = 0
+	exitMismatch = 1
+	exitUsage    = 2
+)
+
+const usage = `usage:
+	ssha hash [-salt-bytes n] [password]
+	ssha verify <stored> [password]
+`
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	// the tool exists to produce {SSHA} values, so SHA-1 is chosen knowingly
+	ssha1.SuppressDeprecationNotice()
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by args[0] and returns the exit
+// status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var (
+		status int
+		err    error
+	)
+	switch args[0] {
+	case "hash":
+		status, err = runHash(args[1:], stdin, stdout, stderr)
+	case "verify":
+		status, err = runVerify(args[1:], stdin, stderr)
+	default:
+		err = fmt.Errorf("%w: unknown subcommand %q", errUsage, args[0])
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "ssha: %v\n", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(stderr, usage)
+		}
+		return exitUsage
+	}
+	return status
+}
+
+func runHash(args []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	fs := flag.NewFlagSet("hash", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	saltBytes := fs.Int("salt-bytes", ssha1.DefaultNumSaltBytes, "number of random salt `bytes`")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+	if fs.NArg() > 1 {
+		return exitUsage, fmt.Errorf("%w: too many arguments", errUsage)
+	}
+
+	password, err := readPassword(fs.Args(), stdin)
+	if err != nil {
+		return exitUsage, err
+	}
+
+	h, err := ssha1.NewForSaltSize(*saltBytes)
+	if err != nil {
+		return exitUsage, err
+	}
+	h.Write(password)
+
+	fmt.Fprintln(stdout, h.String())
+	return exitOK, nil
+}
+
+func runVerify(args []string, stdin io.Reader, stderr io.Writer) (int, error) {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+	if fs.NArg() < 1 {
+		return exitUsage, fmt.Errorf("%w: missing stored hash", errUsage)
+	}
+	if fs.NArg() > 2 {
+		return exitUsage, fmt.Errorf("%w: too many arguments", errUsage)
+	}
+
+	password, err := readPassword(fs.Args()[1:], stdin)
+	if err != nil {
+		return exitUsage, err
+	}
+
+	ok, err := ssha1.Verify(fs.Arg(0), password)
+	if err != nil {
+		return exitUsage, err
+	}
+	if !ok {
+		return exitMismatch, nil
+	}
+	return exitOK, nil
+}
+
+// readPassword returns args[0] if present; otherwise, it reads the
+// password from stdin, removing a single trailing newline.
+func readPassword(args []string, stdin io.Reader) ([]byte, error) {
+	if len(args) > 0 {
+		return []byte(args[0]), nil
+	}
+
+	b, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	if strings.HasSuffix(s, "\n") {
+		s = strings.TrimSuffix(s[:len(s)-1], "\r")
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/crypto/ssha1"
+)
+
+const (
+	// salt: "R*w.5Vmo"
+	stored   = "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="
+	password = "You have to be odd to be number one."
+)
+
+type runCase struct {
+	args     []string
+	stdin    string
+	expected int
+}
+
+func TestRunVerify(t *testing.T) {
+	cases := []runCase{
+		{[]string{"verify", stored, password}, "", exitOK},
+		{[]string{"verify", stored, "You have to be odd to be number two."}, "", exitMismatch},
+		{[]string{"verify", stored}, password + "\n", exitOK},
+		{[]string{"verify", stored}, password + "\r\n", exitOK},
+		{[]string{"verify", stored}, password, exitOK},
+		{[]string{"verify", stored}, password + "\n\n", exitMismatch},
+		{[]string{"verify", "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=", password}, "", exitUsage},
+		{[]string{"verify"}, "", exitUsage},
+		{[]string{"verify", stored, password, "extra"}, "", exitUsage},
+		{[]string{"unknown"}, "", exitUsage},
+		{nil, "", exitUsage},
+	}
+
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		if result := run(c.args, strings.NewReader(c.stdin), &stdout, &stderr); result != c.expected {
+			t.Errorf("run(%q) = %d; expected %d (stderr: %s)", c.args, result, c.expected, stderr.String())
+		}
+	}
+}
+
+func TestRunHash(t *testing.T) {
+	cases := []struct {
+		args     []string
+		stdin    string
+		saltSize int
+	}{
+		{[]string{"hash", password}, "", ssha1.DefaultNumSaltBytes},
+		{[]string{"hash"}, password + "\n", ssha1.DefaultNumSaltBytes},
+		{[]string{"hash", "-salt-bytes", "16", password}, "", 16},
+		{[]string{"hash", "-salt-bytes=4"}, password, 4},
+	}
+
+	for _, c := range cases {
+		var stdout, stderr bytes.Buffer
+		if result := run(c.args, strings.NewReader(c.stdin), &stdout, &stderr); result != exitOK {
+			t.Errorf("run(%q) = %d; expected %d (stderr: %s)", c.args, result, exitOK, stderr.String())
+			continue
+		}
+
+		encoded := strings.TrimSuffix(stdout.String(), "\n")
+		if !strings.HasPrefix(encoded, "{SSHA}") {
+			t.Errorf("run(%q) output = %s; expected {SSHA} prefix", c.args, encoded)
+		}
+		if result, err := ssha1.Verify(encoded, []byte(password)); err != nil || !result {
+			t.Errorf("Verify() of run(%q) output = %t, %v; expected true, nil", c.args, result, err)
+		}
+		if result, err := ssha1.SaltSizeOf(encoded); err != nil || result != c.saltSize {
+			t.Errorf("SaltSizeOf() of run(%q) output = %d, %v; expected %d", c.args, result, err, c.saltSize)
+		}
+	}
+}
+
+func TestRunHashErrors(t *testing.T) {
+	cases := [][]string{
+		{"hash", "-salt-bytes", "0", password},
+		{"hash", "-salt-bytes", "1025", password},
+		{"hash", "-no-such-flag", password},
+		{"hash", password, "extra"},
+	}
+
+	for _, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if result := run(args, strings.NewReader(""), &stdout, &stderr); result != exitUsage {
+			t.Errorf("run(%q) = %d; expected %d", args, result, exitUsage)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("run(%q) wrote %q to stdout; expected nothing", args, stdout.String())
+		}
+	}
+}
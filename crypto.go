@@ -1,13 +1,88 @@
 package crypto
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"hash"
 )
 
+// ErrShortBuffer is returned by SumTo when the destination buffer cannot
+// hold the sum.
+var ErrShortBuffer = errors.New("destination buffer too small for sum")
+
 type Hash interface {
 	hash.Hash
 	fmt.Stringer
 
 	HexString() string
+
+	// Scheme returns the scheme prefix used by String, e.g. "{SSHA}".
+	Scheme() string
+
+	// SaltSize returns the number of salt bytes. Size() always equals the
+	// size of the underlying hash plus SaltSize().
+	SaltSize() int
+}
+
+// URLStringer is implemented by hashes that can also encode their sum with
+// the URL and filename safe base-64 alphabet.
+type URLStringer interface {
+	// URLString is like String, but encodes the sum with the URL and
+	// filename safe base-64 alphabet. The result is not interchangeable
+	// with the output of String.
+	URLString() string
+}
+
+// PrefixStringer is implemented by hashes whose string form can carry a
+// prefix other than their scheme.
+type PrefixStringer interface {
+	// StringWithPrefix is like String, but uses the specified prefix in
+	// place of the scheme prefix. The prefix may be empty.
+	StringWithPrefix(prefix string) string
+}
+
+// Cloner is implemented by hashes that can be copied mid-stream.
+type Cloner interface {
+	// Clone returns an independent copy of the hash, including its salt
+	// and any data written so far.
+	Clone() Hash
+}
+
+// SaltResetter is implemented by hashes that can be reused with a fresh
+// salt, e.g. when hashing a batch of passwords.
+type SaltResetter interface {
+	// ResetWithNewSalt resets the hash to its initial state, like Reset,
+	// and replaces the salt with a new random one of the same size,
+	// generated via the crypto/rand package.
+	ResetWithNewSalt() error
+}
+
+// Salter is implemented by hashes that give access to their salt, as the
+// hashes of this module do. It is kept out of Hash so that implementations
+// of Hash need not support it.
+type Salter interface {
+	// Salt returns a copy of the salt.
+	Salt() []byte
+}
+
+// Equal reports whether a and b produce the same sum, comparing them in
+// constant time. Sums of different sizes are never equal.
+func Equal(a, b Hash) bool {
+	return subtle.ConstantTimeCompare(a.Sum(nil), b.Sum(nil)) == 1
+}
+
+// SumTo writes the current sum of h into dst and returns dst resliced to
+// h.Size(). The capacity of dst must be at least h.Size(), otherwise
+// ErrShortBuffer is returned; dst is never grown. If dst is nil, a new
+// slice is allocated. Reusing dst across calls avoids allocating a new
+// slice for each sum.
+func SumTo(h Hash, dst []byte) ([]byte, error) {
+	if dst == nil {
+		return h.Sum(nil), nil
+	}
+	if cap(dst) < h.Size() {
+		return nil, ErrShortBuffer
+	}
+	return h.Sum(dst[:0]), nil
 }
@@ -0,0 +1,109 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// fakeHash is a minimal salted SHA-1 Hash used to exercise the helpers in
+// this package without depending on the subpackages.
+type fakeHash struct {
+	data []byte
+	salt []byte
+}
+
+func newFakeHash(salt string) *fakeHash { return &fakeHash{salt: []byte(salt)} }
+
+func (f *fakeHash) Write(p []byte) (int, error) {
+	f.data = append(f.data, p...)
+	return len(p), nil
+}
+
+func (f *fakeHash) Sum(in []byte) []byte {
+	tmp := append(append([]byte(nil), f.data...), f.salt...)
+	sum := sha1.Sum(tmp)
+	return append(append(in, sum[:]...), f.salt...)
+}
+
+func (f *fakeHash) Clone() Hash {
+	return &fakeHash{data: append([]byte(nil), f.data...), salt: f.Salt()}
+}
+
+func (f *fakeHash) ResetWithNewSalt() error {
+	f.salt = append([]byte(nil), f.salt...)
+	for i := range f.salt {
+		f.salt[i]++
+	}
+	f.Reset()
+	return nil
+}
+
+func (f *fakeHash) StringWithPrefix(prefix string) string {
+	return prefix + base64.StdEncoding.EncodeToString(f.Sum(nil))
+}
+
+func (f *fakeHash) Scheme() string    { return "{FAKE}" }
+func (f *fakeHash) Reset()            { f.data = nil }
+func (f *fakeHash) Size() int         { return sha1.Size + len(f.salt) }
+func (f *fakeHash) BlockSize() int    { return sha1.BlockSize }
+func (f *fakeHash) SaltSize() int     { return len(f.salt) }
+func (f *fakeHash) Salt() []byte      { return append([]byte(nil), f.salt...) }
+func (f *fakeHash) String() string    { return f.StringWithPrefix("") }
+func (f *fakeHash) URLString() string { return base64.URLEncoding.EncodeToString(f.Sum(nil)) }
+func (f *fakeHash) HexString() string { return hex.EncodeToString(f.Sum(nil)) }
+
+type equalCase struct {
+	saltA, dataA string
+	saltB, dataB string
+	expected     bool
+}
+
+func TestEqual(t *testing.T) {
+	cases := []equalCase{
+		{"n4pggXWL", "supercalifragilisticexpialidocious", "n4pggXWL", "supercalifragilisticexpialidocious", true},
+		{"n4pggXWL", "supercalifragilisticexpialidocious", "K218iReB", "supercalifragilisticexpialidocious", false},
+		{"n4pggXWL", "supercalifragilisticexpialidocious", "n4pggXWL", "abcdefghijklmnopqrstuvwxyz", false},
+		// different salt lengths, therefore different sizes
+		{"n4pggXWL", "supercalifragilisticexpialidocious", "n4pggXWLn4pggXWL", "supercalifragilisticexpialidocious", false},
+	}
+
+	for _, c := range cases {
+		a := newFakeHash(c.saltA)
+		a.Write([]byte(c.dataA))
+		b := newFakeHash(c.saltB)
+		b.Write([]byte(c.dataB))
+
+		if result := Equal(a, b); result != c.expected {
+			t.Errorf("Equal() = %t; expected %t for test case %v", result, c.expected, c)
+		}
+	}
+}
+
+func TestSumTo(t *testing.T) {
+	h := newFakeHash("n4pggXWL")
+	h.Write([]byte("supercalifragilisticexpialidocious"))
+	expected := h.Sum(nil)
+
+	buf := make([]byte, 0, h.Size())
+	result, err := SumTo(h, buf)
+	if err != nil {
+		t.Errorf("SumTo() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(result, expected) {
+		t.Errorf("SumTo result = %x; expected %x", result, expected)
+	}
+	if &result[0] != &buf[:1][0] {
+		t.Errorf("SumTo did not write into the provided buffer")
+	}
+
+	if result, err := SumTo(h, nil); err != nil || !bytes.Equal(result, expected) {
+		t.Errorf("SumTo(nil) = %x, %v; expected %x, nil", result, err, expected)
+	}
+	if _, err := SumTo(h, make([]byte, h.Size()-1)); !errors.Is(err, ErrShortBuffer) {
+		t.Errorf("SumTo() error = %v; expected %v", err, ErrShortBuffer)
+	}
+}
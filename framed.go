@@ -0,0 +1,57 @@
+package crypto
+
+import "errors"
+
+// MaxFramedSaltBytes specifies the maximum number of salt bytes that fit in
+// the 1-byte length header written by FramedSum.
+const MaxFramedSaltBytes int = 255
+
+// Errors returned by FramedSum and ParseFramed.
+var (
+	// ErrFramedSaltTooLong is returned by FramedSum when the salt is longer
+	// than MaxFramedSaltBytes.
+	ErrFramedSaltTooLong = errors.New("salt too long to frame, must be at most 255 bytes")
+
+	// ErrMalformedFrame is returned by ParseFramed when a blob is too short
+	// for its salt-length header.
+	ErrMalformedFrame = errors.New("malformed framed sum")
+)
+
+// FramedSum returns the current sum of h in a self-describing framing:
+// a 1-byte salt length followed by the digest and the salt, i.e.
+// len(salt) || H(data || salt) || salt. As the salt length is recorded, a
+// reader can split the blob with ParseFramed without knowing which hash
+// algorithm produced it. Salts longer than MaxFramedSaltBytes cannot be
+// framed and yield ErrFramedSaltTooLong.
+//
+// The framing is specific to this module and is not compatible with the
+// "{SCHEME}" formats produced by String or accepted by ValidateAny.
+func FramedSum(h Hash) ([]byte, error) {
+	saltSize := h.SaltSize()
+	if saltSize > MaxFramedSaltBytes {
+		return nil, ErrFramedSaltTooLong
+	}
+	return h.Sum([]byte{byte(saltSize)}), nil
+}
+
+// ParseFramed splits a blob produced by FramedSum into its digest and salt.
+// The returned slices alias b. ParseFramed returns ErrMalformedFrame if b
+// is empty or too short to hold a non-empty digest and the salt announced
+// by its header, and ErrSaltTooShort if the header announces no salt.
+func ParseFramed(b []byte) (digest, salt []byte, err error) {
+	if len(b) == 0 {
+		return nil, nil, ErrMalformedFrame
+	}
+
+	saltSize := int(b[0])
+	if saltSize < MinSaltBytes {
+		return nil, nil, ErrSaltTooShort
+	}
+	body := b[1:]
+	if len(body) <= saltSize {
+		return nil, nil, ErrMalformedFrame
+	}
+
+	digestSize := len(body) - saltSize
+	return body[:digestSize], body[digestSize:], nil
+}
@@ -0,0 +1,94 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"testing"
+)
+
+type framedCase struct {
+	newHash  func() hash.Hash
+	saltSize int
+}
+
+func TestFramedSumRoundTrip(t *testing.T) {
+	cases := []framedCase{
+		{sha1.New, 1},
+		{sha1.New, 8},
+		{sha1.New, 20},
+		{sha256.New, 16},
+		{sha512.New, 64},
+		{sha512.New, MaxFramedSaltBytes},
+	}
+
+	plaintext := []byte("All that glitters is not gold.")
+	for _, c := range cases {
+		salt := bytes.Repeat([]byte{'s'}, c.saltSize)
+		h, err := NewSalted(c.newHash, salt)
+		if err != nil {
+			t.Errorf("NewSalted() returned unexpected error: %e", err)
+			continue
+		}
+		h.Write(plaintext)
+
+		framed, err := FramedSum(h)
+		if err != nil {
+			t.Errorf("FramedSum() returned unexpected error: %e", err)
+			continue
+		}
+		if len(framed) != 1+h.Size() {
+			t.Errorf("len(FramedSum()) = %d; expected %d", len(framed), 1+h.Size())
+		}
+
+		digest, parsedSalt, err := ParseFramed(framed)
+		if err != nil {
+			t.Errorf("ParseFramed() returned unexpected error: %e", err)
+			continue
+		}
+		if !bytes.Equal(parsedSalt, salt) {
+			t.Errorf("salt = %q; expected %q", parsedSalt, salt)
+		}
+
+		raw := c.newHash()
+		raw.Write(plaintext)
+		raw.Write(salt)
+		if expected := raw.Sum(nil); !bytes.Equal(digest, expected) {
+			t.Errorf("digest = %x; expected %x", digest, expected)
+		}
+	}
+}
+
+func TestFramedSumErrors(t *testing.T) {
+	h, err := NewSalted(sha1.New, make([]byte, MaxFramedSaltBytes+1))
+	if err != nil {
+		t.Fatalf("NewSalted() returned unexpected error: %e", err)
+	}
+	if _, err := FramedSum(h); !errors.Is(err, ErrFramedSaltTooLong) {
+		t.Errorf("FramedSum() error = %v; expected %v", err, ErrFramedSaltTooLong)
+	}
+}
+
+type parseFramedCase struct {
+	framed   []byte
+	expected error
+}
+
+func TestParseFramedErrors(t *testing.T) {
+	cases := []parseFramedCase{
+		{nil, ErrMalformedFrame},
+		{[]byte{}, ErrMalformedFrame},
+		{[]byte{0, 'd', 'd'}, ErrSaltTooShort},
+		{[]byte{2, 's', 's'}, ErrMalformedFrame}, // no digest
+		{[]byte{4, 'd', 's'}, ErrMalformedFrame},
+	}
+
+	for _, c := range cases {
+		if _, _, err := ParseFramed(c.framed); !errors.Is(err, c.expected) {
+			t.Errorf("ParseFramed(%x) error = %v; expected %v", c.framed, err, c.expected)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package crypto
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Validator reports whether the hash of sample matches the decoded hash.
+type Validator func(hash, sample []byte) (bool, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Validator)
+)
+
+// Register makes a validator available to ValidateAny for the given scheme
+// prefix, e.g. "{SSHA}". Salted-hash packages register themselves from
+// their init functions. If Register is called twice with the same scheme
+// or if validator is nil, it panics.
+func Register(scheme string, validator Validator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if validator == nil {
+		panic("crypto: Register validator is nil")
+	}
+	for registered := range registry {
+		if strings.EqualFold(registered, scheme) {
+			panic("crypto: Register called twice for scheme " + scheme)
+		}
+	}
+	registry[scheme] = validator
+}
+
+// ValidateAny returns true if the hash of the sample matches the encoded
+// "{SCHEME}base64" hash, using the validator registered for its scheme;
+// false, otherwise. The package implementing the scheme must be imported
+// (for its side effects, if nothing else) for the scheme to be supported.
+func ValidateAny(encoded string, sample []byte) (bool, error) {
+	scheme, payload, err := ParseScheme(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	registryMu.RLock()
+	validator := registry[scheme]
+	registryMu.RUnlock()
+	if validator == nil {
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedScheme, scheme)
+	}
+
+	return validator(payload, sample)
+}
+
+// VerifyAny returns true if password matches the stored "{SCHEME}base64"
+// hash, whatever salted scheme it uses, e.g. "{SSHA}", "{SSHA256}" or
+// "{SMD5}"; false, otherwise. The packages implementing the schemes in use
+// must be imported, as for ValidateAny, whose behavior VerifyAny shares:
+// an unrecognized scheme yields ErrUnknownScheme and a recognized one
+// whose package is not imported ErrUnsupportedScheme.
+func VerifyAny(stored string, password []byte) (bool, error) {
+	return ValidateAny(stored, password)
+}
+
+// SupportedSchemes returns the scheme prefixes that currently have a
+// registered validator, sorted for stable display. As for ValidateAny, a
+// scheme is only listed if the package implementing it has been imported.
+func SupportedSchemes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	schemes := make([]string, 0, len(registry))
+	for scheme := range registry {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+func registeredScheme(s string) (string, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for registered := range registry {
+		if strings.EqualFold(registered, s) {
+			return registered, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,189 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// withRegistry snapshots the global registry and restores it when t and
+// its subtests finish, so that t may register schemes freely. Without it,
+// running the tests twice, e.g. with -count=2, panics on the duplicates.
+func withRegistry(t *testing.T) {
+	t.Helper()
+	registryMu.Lock()
+	saved := make(map[string]Validator, len(registry))
+	for scheme, validator := range registry {
+		saved[scheme] = validator
+	}
+	registryMu.Unlock()
+
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+}
+
+func TestValidateAny(t *testing.T) {
+	withRegistry(t)
+
+	// the fake scheme's "hash" is simply the sample itself
+	Register("{FAKE}", func(hash, sample []byte) (bool, error) {
+		return bytes.Equal(hash, sample), nil
+	})
+
+	encoded := "{FAKE}" + base64.StdEncoding.EncodeToString([]byte("open sesame"))
+
+	if result, err := ValidateAny(encoded, []byte("open sesame")); err != nil || !result {
+		t.Errorf("ValidateAny() = %t, %v; expected true, nil", result, err)
+	}
+	if result, err := ValidateAny(encoded, []byte("open barley")); err != nil || result {
+		t.Errorf("ValidateAny() = %t, %v; expected false, nil", result, err)
+	}
+
+	// {SHA} is recognized, but nothing has registered a validator for it
+	if _, err := ValidateAny("{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=", []byte("password")); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("ValidateAny() error = %v; expected %v", err, ErrUnsupportedScheme)
+	}
+	if _, err := ValidateAny("{BOGUS}AAAA", nil); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("ValidateAny() error = %v; expected %v", err, ErrUnknownScheme)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	withRegistry(t)
+
+	validator := func(hash, sample []byte) (bool, error) { return false, nil }
+	Register("{DUPLICATE}", validator)
+
+	cases := []struct {
+		scheme    string
+		validator Validator
+	}{
+		{"{DUPLICATE}", validator},
+		{"{duplicate}", validator},
+		{"{NIL}", nil},
+	}
+
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", c.scheme)
+				}
+			}()
+			Register(c.scheme, c.validator)
+		}()
+	}
+}
+
+// saltedValidator returns a validator for hashes produced by NewSalted with
+// newHash, standing in for the one registered by the corresponding
+// package, which cannot be imported here.
+func saltedValidator(newHash func() hash.Hash) Validator {
+	return func(stored, sample []byte) (bool, error) {
+		size := newHash().Size()
+		if len(stored) <= size {
+			return false, ErrSaltTooShort
+		}
+		h, err := NewSalted(newHash, stored[size:])
+		if err != nil {
+			return false, err
+		}
+		h.Write(sample)
+		return subtle.ConstantTimeCompare(stored, h.Sum(nil)) == 1, nil
+	}
+}
+
+type verifyAnyCase struct {
+	scheme  string
+	newHash func() hash.Hash
+	stored  string
+}
+
+func TestVerifyAny(t *testing.T) {
+	withRegistry(t)
+
+	// password "1234567890", salt "abcdefg"; computed independently
+	cases := []verifyAnyCase{
+		{SchemeSSHA, sha1.New, "{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn"},
+		{SchemeSSHA224, sha256.New224, "{SSHA224}JHaN0S8I2qN9v9+mbbX+WnLY3FLVDIQdOzuFMGFiY2RlZmc="},
+		{SchemeSSHA256, sha256.New, "{SSHA256}iKAPRoNs1inQt53phTKv3jrq15pcU+SEgQL0MwRtAQZhYmNkZWZn"},
+		{SchemeSSHA384, sha512.New384, "{SSHA384}lfZheKy2vcC0W07cnI14P3sqQogy8FLo3Ouo0Nd+F1XmovXX6ImcQxuXD4rF0cJoYWJjZGVmZw=="},
+		{SchemeSSHA512, sha512.New, "{SSHA512}AUiIVopMkvOkoDM/SvodM1TgpxT7aVa4PjDmtjZtOTs8Oy8Jbyl9V/u4v/Ea7cUuYGtMQjYQtyLcxKRqbiRCPmFiY2RlZmc="},
+		{SchemeSMD5, md5.New, "{SMD5}cgbd+lEbarBXNLYDwbiL5mFiY2RlZmc="},
+	}
+
+	for _, c := range cases {
+		Register(c.scheme, saltedValidator(c.newHash))
+	}
+
+	for _, c := range cases {
+		if result, err := VerifyAny(c.stored, []byte("1234567890")); err != nil || !result {
+			t.Errorf("VerifyAny(%q) = %t, %v; expected true, nil", c.stored, result, err)
+		}
+		if result, err := VerifyAny(c.stored, []byte("123456789")); err != nil || result {
+			t.Errorf("VerifyAny(%q) = %t, %v; expected false, nil", c.stored, result, err)
+		}
+	}
+
+	if _, err := VerifyAny("{BOGUS}AAAA", nil); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("VerifyAny() error = %v; expected %v", err, ErrUnknownScheme)
+	}
+	if _, err := VerifyAny("{CRYPT}aaX/1htJhjFEw", nil); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("VerifyAny() error = %v; expected %v", err, ErrUnsupportedScheme)
+	}
+	if _, err := VerifyAny("hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", nil); !errors.Is(err, ErrNoScheme) {
+		t.Errorf("VerifyAny() error = %v; expected %v", err, ErrNoScheme)
+	}
+}
+
+func TestSupportedSchemes(t *testing.T) {
+	before := SupportedSchemes()
+
+	t.Run("registered", func(t *testing.T) {
+		// start from an empty registry, so that the result does not depend
+		// on what other tests registered
+		withRegistry(t)
+		registryMu.Lock()
+		registry = make(map[string]Validator)
+		registryMu.Unlock()
+
+		if schemes := SupportedSchemes(); len(schemes) != 0 {
+			t.Errorf("SupportedSchemes() = %q; expected none", schemes)
+		}
+
+		validator := func(hash, sample []byte) (bool, error) { return false, nil }
+		Register("{LISTED-B}", validator)
+		Register("{LISTED-A}", validator)
+
+		schemes := SupportedSchemes()
+		if expected := []string{"{LISTED-A}", "{LISTED-B}"}; !reflect.DeepEqual(schemes, expected) {
+			t.Errorf("SupportedSchemes() = %q; expected %q", schemes, expected)
+		}
+
+		// the returned slice is a copy
+		schemes[0] = "{CHANGED}"
+		if SupportedSchemes()[0] == "{CHANGED}" {
+			t.Errorf("modifying the slice returned by SupportedSchemes() changed the registry")
+		}
+	})
+
+	// the schemes registered above are gone once the registry is restored
+	if after := SupportedSchemes(); !reflect.DeepEqual(after, before) {
+		t.Errorf("SupportedSchemes() after restoring the registry = %q; expected %q", after, before)
+	}
+	if !sort.StringsAreSorted(before) {
+		t.Errorf("SupportedSchemes() = %q; expected a sorted slice", before)
+	}
+}
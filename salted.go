@@ -0,0 +1,256 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+const (
+	// DefaultNumSaltBytes specifies the default number of salt bytes used
+	// by the salted-hash packages of this module. They share this value, so
+	// that their defaults cannot drift apart.
+	DefaultNumSaltBytes int = 20
+
+	// MinSaltBytes specifies the minimum allowed number of salt bytes.
+	MinSaltBytes int = 1
+
+	// MaxSaltBytes specifies the maximum allowed number of salt bytes.
+	MaxSaltBytes int = 1024
+
+	// number of reads ReadSalt makes before giving up on a weak source, and
+	// the default for ReadSaltAttempts
+	saltReadAttempts int = 8
+)
+
+// Errors returned by NewSalted.
+var (
+	// ErrNilSalt is returned when a nil salt is passed where a salt is
+	// required. An empty, non-nil salt yields ErrSaltTooShort instead.
+	ErrNilSalt = errors.New("salt is nil")
+
+	// ErrSaltTooShort is returned when a salt is shorter than MinSaltBytes.
+	ErrSaltTooShort = errors.New("invalid salt length, must be at least 1 byte")
+
+	// ErrSaltTooLong is returned when a salt is longer than MaxSaltBytes.
+	ErrSaltTooLong = errors.New("invalid salt length, must be at most 1024 bytes")
+
+	// ErrWeakSalt is returned by ReadSalt when the randomness source keeps
+	// producing salts of identical bytes.
+	ErrWeakSalt = errors.New("randomness source repeatedly produced a weak salt")
+
+	// ErrUnsupportedHash is returned when the hash returned by the factory
+	// passed to NewSalted cannot marshal its state.
+	ErrUnsupportedHash = errors.New("hash does not implement encoding.BinaryMarshaler and encoding.BinaryUnmarshaler")
+)
+
+// NewSalted returns a new Hash that computes H(data || salt) || salt, where
+// H is created by newHash, e.g. sha1.New or sha256.New. Salt size must be
+// between 1 and 1024 bytes; a nil salt yields ErrNilSalt. The hashes
+// created by newHash must implement encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler, as all of the standard library hashes do.
+//
+// The String, URLString and StringWithPrefix methods of the returned Hash
+// know nothing of a scheme, so String and URLString return the bare
+// base-64 encoded sum. The returned Hash also implements URLStringer,
+// PrefixStringer, Cloner, SaltResetter and Salter.
+func NewSalted(newHash func() hash.Hash, salt []byte) (Hash, error) {
+	if salt == nil {
+		return nil, ErrNilSalt
+	}
+	if len(salt) < MinSaltBytes {
+		return nil, ErrSaltTooShort
+	}
+	if len(salt) > MaxSaltBytes {
+		return nil, ErrSaltTooLong
+	}
+
+	h := newHash()
+	if !supportsState(h) {
+		return nil, ErrUnsupportedHash
+	}
+
+	return &salted{newHash: newHash, h: h, salt: salt}, nil
+}
+
+type salted struct {
+	newHash func() hash.Hash
+	h       hash.Hash
+	salt    []byte
+}
+
+// Size returns the number of bytes Sum will return, the size of the
+// underlying hash plus the number of salt bytes.
+func (s *salted) Size() int { return s.h.Size() + len(s.salt) } // hash.Hash interface
+
+// BlockSize returns the underlying hash's block size.
+func (s *salted) BlockSize() int { return s.h.BlockSize() } // hash.Hash interface
+
+// SaltSize returns the number of salt bytes.
+func (s *salted) SaltSize() int { return len(s.salt) } // Hash interface
+
+// Salt returns a copy of the salt. Modifying the returned slice does not
+// affect the hash.
+func (s *salted) Salt() []byte { // Salter interface
+	return append([]byte(nil), s.salt...)
+}
+
+// Clone returns an independent copy of the hash, including its salt and
+// any data written so far.
+func (s *salted) Clone() Hash { // Cloner interface
+	return &salted{newHash: s.newHash, h: SnapshotHash(s.h, s.newHash), salt: s.Salt()}
+}
+
+// Reset resets the Hash to its initial state. The salt will remain unchanged.
+func (s *salted) Reset() { s.h.Reset() } // hash.Hash interface
+
+// ResetWithNewSalt resets the Hash to its initial state and replaces the
+// salt with a new random one of the same size, generated via the
+// crypto/rand package. A hash with no salt yields ErrSaltTooShort, as
+// there is no size to preserve.
+func (s *salted) ResetWithNewSalt() error { // SaltResetter interface
+	if len(s.salt) == 0 {
+		return ErrSaltTooShort
+	}
+	salt := make([]byte, len(s.salt))
+	if err := ReadSalt(rand.Reader, salt); err != nil {
+		return err
+	}
+	s.salt = salt
+	s.Reset()
+	return nil
+}
+
+// ReadSalt fills salt with bytes read from r, typically rand.Reader. As a
+// cheap sanity check of the source, a salt of two or more bytes that are
+// all identical, e.g. all zeros from a misconfigured generator, is
+// discarded and read again. If that happens on every one of a few
+// attempts, ErrWeakSalt is returned. Errors from r are returned as is.
+func ReadSalt(r io.Reader, salt []byte) error {
+	return ReadSaltAttempts(r, salt, saltReadAttempts)
+}
+
+// ReadSaltAttempts is like ReadSalt, but reads a weak salt again up to
+// attempts times in all, so that the retry budget can be tuned; an attempts
+// of 0 or less selects the default of ReadSalt. ErrWeakSalt is returned
+// wrapped with the number of attempts made. With a healthy randomness
+// source, a weak salt of more than a few bytes is so unlikely that the
+// retries should essentially never happen.
+func ReadSaltAttempts(r io.Reader, salt []byte, attempts int) error {
+	if attempts <= 0 {
+		attempts = saltReadAttempts
+	}
+	for i := 0; i < attempts; i++ {
+		if _, err := io.ReadFull(r, salt); err != nil {
+			return err
+		}
+		if !isUniform(salt) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d attempts made", ErrWeakSalt, attempts)
+}
+
+// isUniform reports whether b has two or more bytes, all identical.
+func isUniform(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	for _, c := range b[1:] {
+		if c != b[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Write adds more data to the running hash.
+// It never returns an error.
+func (s *salted) Write(p []byte) (int, error) { // io.Writer interface
+	return s.h.Write(p)
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state: Sum does not finalize the
+// hash, and further writes extend the data hashed so far. Sum panics if the
+// hash has no salt; NewSalted never returns such a hash.
+func (s *salted) Sum(in []byte) []byte { // hash.Hash interface
+	if len(s.salt) == 0 {
+		panic("crypto: Sum called on a salted hash with no salt")
+	}
+	return AppendSaltedSum(in, s.h, s.newHash, nil, s.salt)
+}
+
+// Scheme returns an empty string, as the hash has no scheme prefix.
+func (s *salted) Scheme() string { return "" } // Hash interface
+
+// String returns the base-64 encoded sum, without a scheme prefix.
+func (s *salted) String() string { // fmt.Stringer interface
+	return s.StringWithPrefix("")
+}
+
+// StringWithPrefix returns the base-64 encoded sum, prefixed with the
+// specified prefix.
+func (s *salted) StringWithPrefix(prefix string) string { // PrefixStringer interface
+	return prefix + base64.StdEncoding.EncodeToString(s.Sum(nil))
+}
+
+// URLString returns the sum encoded with the URL and filename safe base-64
+// alphabet, without a scheme prefix.
+func (s *salted) URLString() string { // URLStringer interface
+	return base64.URLEncoding.EncodeToString(s.Sum(nil))
+}
+
+// HexString returns the sum as a hexadecimal string.
+func (s *salted) HexString() string { // Hash interface
+	return hex.EncodeToString(s.Sum(nil))
+}
+
+// supportsState reports whether h can marshal and unmarshal its state, as
+// required by SnapshotHash.
+func supportsState(h hash.Hash) bool {
+	if _, ok := h.(encoding.BinaryMarshaler); !ok {
+		return false
+	}
+	_, ok := h.(encoding.BinaryUnmarshaler)
+	return ok
+}
+
+// SnapshotHash returns a copy of the running hash h, which was created by
+// newHash, allowing a salt to be mixed in without disturbing the state of
+// h. h must implement encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler, as all of the standard library hashes do;
+// SnapshotHash panics otherwise, or if the state cannot be restored.
+func SnapshotHash(h hash.Hash, newHash func() hash.Hash) hash.Hash {
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	c := newHash()
+	if err := c.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// FinishSaltedSum writes the pepper and the salt to h, which holds the data,
+// and appends H(data || pepper || salt) || salt to in, the layout of the
+// sums of this module. h is left with the pepper and salt written, so it
+// should be reset before further use. The pepper may be nil.
+func FinishSaltedSum(in []byte, h hash.Hash, pepper, salt []byte) []byte {
+	h.Write(pepper)
+	h.Write(salt)
+	return append(h.Sum(in), salt...)
+}
+
+// AppendSaltedSum is like FinishSaltedSum, but works on a snapshot of h
+// taken by SnapshotHash, so h is not affected and further writes extend
+// the data hashed so far.
+func AppendSaltedSum(in []byte, h hash.Hash, newHash func() hash.Hash, pepper, salt []byte) []byte {
+	return FinishSaltedSum(in, SnapshotHash(h, newHash), pepper, salt)
+}
@@ -0,0 +1,279 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+	"testing"
+)
+
+type saltedCase struct {
+	newHash           func() hash.Hash
+	size              int
+	plaintext         []byte
+	salt              []byte
+	expectedHexString string
+}
+
+func TestNewSalted(t *testing.T) {
+	cases := []saltedCase{
+		// vectors from the ssha1 and ssha256 packages
+		{sha1.New, sha1.Size, []byte("supercalifragilisticexpialidocious"), []byte("n4pggXWL"), "8eadde532169b6908034886be119c9f0ca61801e6e3470676758574c"},
+		{sha1.New, sha1.Size, []byte("1234567890"), []byte("abcdefg"), "8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667"},
+		{sha256.New, sha256.Size, []byte("supercalifragilisticexpialidocious"), []byte("n4pggXWL"), "ff4504d825c7f468530d34f6bd44b2fe270ed7c126296d9da6381e03b7c462df6e3470676758574c"},
+		{sha256.New, sha256.Size, []byte("1234567890"), []byte("abcdefg"), "88a00f46836cd629d0b79de98532afde3aead79a5c53e4848102f433046d010661626364656667"},
+	}
+
+	for _, c := range cases {
+		h, err := NewSalted(c.newHash, c.salt)
+		if err != nil {
+			t.Errorf("NewSalted() returned unexpected error: %e", err)
+			continue
+		}
+
+		h.Write(c.plaintext)
+		if result := h.HexString(); result != c.expectedHexString {
+			t.Errorf("result = %s; expected %s", result, c.expectedHexString)
+		}
+		if result := h.Size(); result != c.size+len(c.salt) {
+			t.Errorf("Size result = %d; expected %d", result, c.size+len(c.salt))
+		}
+		if result := h.SaltSize(); result != len(c.salt) {
+			t.Errorf("SaltSize result = %d; expected %d", result, len(c.salt))
+		}
+		if result := h.String(); result != h.(PrefixStringer).StringWithPrefix("") {
+			t.Errorf("String result = %s; expected %s", result, h.(PrefixStringer).StringWithPrefix(""))
+		}
+	}
+}
+
+func TestNewSaltedState(t *testing.T) {
+	h, err := NewSalted(sha256.New, []byte("tH3g5qLx"))
+	if err != nil {
+		t.Errorf("NewSalted() returned unexpected error: %e", err)
+	}
+
+	h.Write([]byte("The quick brown fox "))
+	clone := h.(Cloner).Clone()
+	sum1 := h.Sum(nil)
+	if sum2 := h.Sum(nil); !bytes.Equal(sum1, sum2) {
+		t.Errorf("repeated Sum results differ: %x and %x", sum1, sum2)
+	}
+
+	h.Write([]byte("jumps over the lazy dog."))
+	if result := clone.Sum(nil); !bytes.Equal(result, sum1) {
+		t.Errorf("clone Sum result = %x; expected %x", result, sum1)
+	}
+
+	h.Reset()
+	h.Write([]byte("The quick brown fox "))
+	if result := h.Sum(nil); !bytes.Equal(result, sum1) {
+		t.Errorf("Sum result after Reset = %x; expected %x", result, sum1)
+	}
+}
+
+func TestNewSaltedErrors(t *testing.T) {
+	if _, err := NewSalted(sha1.New, nil); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("NewSalted(nil) error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := NewSalted(sha1.New, []byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewSalted() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewSalted(sha1.New, make([]byte, MaxSaltBytes+1)); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewSalted() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+
+	// wrapping the hash hides its state marshaling methods
+	opaque := func() hash.Hash { return struct{ hash.Hash }{sha1.New()} }
+	if _, err := NewSalted(opaque, []byte("abcdefg")); !errors.Is(err, ErrUnsupportedHash) {
+		t.Errorf("NewSalted() error = %v; expected %v", err, ErrUnsupportedHash)
+	}
+}
+
+func TestNewSaltedScheme(t *testing.T) {
+	h, err := NewSalted(sha1.New, []byte("abcdefg"))
+	if err != nil {
+		t.Errorf("NewSalted() returned unexpected error: %e", err)
+	}
+	if result := h.Scheme(); result != "" {
+		t.Errorf("Scheme result = %q; expected empty", result)
+	}
+}
+
+func TestNewSaltedSumWithoutSaltPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Sum() on a salted hash with no salt did not panic")
+		}
+	}()
+	s := &salted{newHash: sha1.New, h: sha1.New()}
+	s.Sum(nil)
+}
+
+func TestNewSaltedResetWithNewSaltWithoutSalt(t *testing.T) {
+	s := &salted{newHash: sha1.New, h: sha1.New()}
+	if err := s.ResetWithNewSalt(); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("ResetWithNewSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
+
+// zeroThenReader returns zeros for the first n reads, then reads from r.
+type zeroThenReader struct {
+	n     int
+	r     io.Reader
+	reads int
+}
+
+func (z *zeroThenReader) Read(p []byte) (int, error) {
+	z.reads++
+	if z.reads <= z.n {
+		for i := range p {
+			p[i] = 0
+		}
+		return len(p), nil
+	}
+	return z.r.Read(p)
+}
+
+func TestReadSalt(t *testing.T) {
+	source := []byte("0123456789abcdef")
+
+	// a weak salt is discarded and read again
+	r := &zeroThenReader{n: 3, r: bytes.NewReader(source)}
+	salt := make([]byte, 8)
+	if err := ReadSalt(r, salt); err != nil {
+		t.Errorf("ReadSalt() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(salt, source[:8]) {
+		t.Errorf("salt = %q; expected %q", salt, source[:8])
+	}
+	if r.reads != 4 {
+		t.Errorf("ReadSalt() made %d reads; expected 4", r.reads)
+	}
+
+	// a source that keeps failing the check is given up on
+	r = &zeroThenReader{n: saltReadAttempts, r: bytes.NewReader(source)}
+	if err := ReadSalt(r, salt); !errors.Is(err, ErrWeakSalt) {
+		t.Errorf("ReadSalt() error = %v; expected %v", err, ErrWeakSalt)
+	}
+	if r.reads != saltReadAttempts {
+		t.Errorf("ReadSalt() made %d reads; expected %d", r.reads, saltReadAttempts)
+	}
+
+	// a single byte cannot be judged, so zero is accepted
+	single := []byte{0xff}
+	if err := ReadSalt(&zeroThenReader{n: 1}, single); err != nil || single[0] != 0 {
+		t.Errorf("ReadSalt() = %x, %v; expected 00, nil", single, err)
+	}
+
+	// errors from the source are passed on
+	if err := ReadSalt(bytes.NewReader(source[:4]), salt); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadSalt() error = %v; expected %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestReadSaltAttempts(t *testing.T) {
+	source := []byte("0123456789abcdef")
+	salt := make([]byte, 8)
+
+	// the budget covers the weak reads
+	r := &zeroThenReader{n: 2, r: bytes.NewReader(source)}
+	if err := ReadSaltAttempts(r, salt, 3); err != nil {
+		t.Errorf("ReadSaltAttempts() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(salt, source[:8]) || r.reads != 3 {
+		t.Errorf("ReadSaltAttempts() = %q after %d reads; expected %q after 3", salt, r.reads, source[:8])
+	}
+
+	// the budget is exhausted
+	r = &zeroThenReader{n: 3, r: bytes.NewReader(source)}
+	err := ReadSaltAttempts(r, salt, 3)
+	if !errors.Is(err, ErrWeakSalt) {
+		t.Errorf("ReadSaltAttempts() error = %v; expected %v", err, ErrWeakSalt)
+	}
+	if err != nil && !strings.Contains(err.Error(), "3 attempts") {
+		t.Errorf("ReadSaltAttempts() error = %q; expected it to contain %q", err, "3 attempts")
+	}
+	if r.reads != 3 {
+		t.Errorf("ReadSaltAttempts() made %d reads; expected 3", r.reads)
+	}
+
+	// no budget selects the default
+	r = &zeroThenReader{n: saltReadAttempts - 1, r: bytes.NewReader(source)}
+	if err := ReadSaltAttempts(r, salt, 0); err != nil {
+		t.Errorf("ReadSaltAttempts() returned unexpected error: %e", err)
+	}
+}
+
+func TestSaltConstants(t *testing.T) {
+	// changing these changes the defaults of every package in the module
+	if DefaultNumSaltBytes != 20 {
+		t.Errorf("DefaultNumSaltBytes = %d; expected 20", DefaultNumSaltBytes)
+	}
+	if MinSaltBytes != 1 {
+		t.Errorf("MinSaltBytes = %d; expected 1", MinSaltBytes)
+	}
+	if MaxSaltBytes != 1024 {
+		t.Errorf("MaxSaltBytes = %d; expected 1024", MaxSaltBytes)
+	}
+}
+
+type unmarshalableHash struct {
+	hash.Hash
+}
+
+func TestSupportsState(t *testing.T) {
+	if !supportsState(sha1.New()) {
+		t.Errorf("supportsState(sha1.New()) = false; expected true")
+	}
+	if supportsState(unmarshalableHash{sha1.New()}) {
+		t.Errorf("supportsState() of a hash hiding its state = true; expected false")
+	}
+}
+
+func TestAppendSaltedSum(t *testing.T) {
+	// salt: "abcdefg"
+	expected, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+	salt := []byte("abcdefg")
+
+	h := sha1.New()
+	h.Write([]byte("12345"))
+	if result := AppendSaltedSum(nil, h, sha1.New, nil, salt); bytes.Equal(result, expected) {
+		t.Errorf("AppendSaltedSum result of partial data = %x; expected a different sum", result)
+	}
+
+	// the snapshot leaves h untouched, so writing on yields the full sum
+	h.Write([]byte("67890"))
+	if result := AppendSaltedSum([]byte("in"), h, sha1.New, nil, salt); !bytes.Equal(result, append([]byte("in"), expected...)) {
+		t.Errorf("AppendSaltedSum result = %x; expected %x", result, append([]byte("in"), expected...))
+	}
+
+	// a pepper goes between the data and the salt
+	h.Reset()
+	h.Write([]byte("12345"))
+	if result := AppendSaltedSum(nil, h, sha1.New, []byte("67890"), salt); !bytes.Equal(result, expected) {
+		t.Errorf("AppendSaltedSum result with pepper = %x; expected %x", result, expected)
+	}
+}
+
+func TestFinishSaltedSum(t *testing.T) {
+	// salt: "abcdefg"
+	expected, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+
+	h := sha1.New()
+	h.Write([]byte("1234567890"))
+	if result := FinishSaltedSum(nil, h, nil, []byte("abcdefg")); !bytes.Equal(result, expected) {
+		t.Errorf("FinishSaltedSum result = %x; expected %x", result, expected)
+	}
+}
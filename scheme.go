@@ -0,0 +1,119 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Scheme prefixes recognized by ParseScheme.
+const (
+	SchemeSHA     string = "{SHA}"
+	SchemeSSHA    string = "{SSHA}"
+	SchemeSSHA224 string = "{SSHA224}"
+	SchemeSSHA256 string = "{SSHA256}"
+	SchemeSSHA384 string = "{SSHA384}"
+	SchemeSSHA512 string = "{SSHA512}"
+	SchemeSMD5    string = "{SMD5}"
+
+	// SchemeCRYPT is the RFC 2307 scheme for Unix crypt(3) hashes. It is
+	// recognized, so that such values can be told apart from unknown ones,
+	// but not supported: its payload is not base-64 encoded.
+	SchemeCRYPT string = "{CRYPT}"
+)
+
+// Errors returned by ParseScheme and ValidateAny.
+var (
+	// ErrNoScheme is returned when an encoded hash has no "{...}" scheme
+	// prefix at all, e.g. a bare base-64 string.
+	ErrNoScheme = errors.New("missing scheme prefix")
+
+	// ErrMalformedScheme is returned when an encoded hash starts with "{"
+	// but the scheme is not terminated by "}" or is empty.
+	ErrMalformedScheme = errors.New("malformed scheme prefix")
+
+	// ErrUnknownScheme is returned when the scheme prefix is well-formed but
+	// not recognized.
+	ErrUnknownScheme = errors.New("unknown scheme")
+
+	// ErrUnsupportedScheme is returned by ValidateAny when the scheme is
+	// recognized but no validator has been registered for it, and by
+	// ParseScheme for recognized schemes it cannot decode.
+	ErrUnsupportedScheme = errors.New("unsupported scheme")
+
+	// ErrInvalidBase64 is returned when the payload following the scheme
+	// prefix is not valid base-64.
+	ErrInvalidBase64 = errors.New("invalid base64 encoding")
+)
+
+var knownSchemes = []string{
+	SchemeSHA,
+	SchemeSSHA,
+	SchemeSSHA224,
+	SchemeSSHA256,
+	SchemeSSHA384,
+	SchemeSSHA512,
+	SchemeSMD5,
+	SchemeCRYPT,
+}
+
+// ParseScheme splits an encoded hash of the form "{SCHEME}base64" into its
+// scheme prefix and base-64 decoded payload. The payload's trailing "="
+// padding may be omitted. The built-in schemes and any scheme passed to
+// Register are recognized. Scheme names are matched case-insensitively, as
+// in RFC 2307, and returned in their canonical form, e.g. "{SSHA}".
+//
+// For "{CRYPT}", which is recognized but cannot be decoded, the scheme is
+// returned along with ErrUnsupportedScheme, so that callers can route such
+// values to an external verifier.
+func ParseScheme(s string) (scheme string, payload []byte, err error) {
+	if !strings.HasPrefix(s, "{") {
+		return "", nil, ErrNoScheme
+	}
+
+	end := strings.IndexByte(s, '}')
+	if end < 2 {
+		return "", nil, ErrMalformedScheme
+	}
+
+	scheme, ok := canonicalScheme(s[:end+1])
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownScheme, s[:end+1])
+	}
+	if scheme == SchemeCRYPT {
+		return scheme, nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, scheme)
+	}
+
+	payload, err = DecodeBase64(s[end+1:])
+	if err != nil {
+		return "", nil, err
+	}
+
+	return scheme, payload, nil
+}
+
+// DecodeBase64 decodes s as padded standard base-64 or, if s carries no
+// padding, as unpadded standard base-64, as ParseScheme does for the
+// payload. Errors wrap ErrInvalidBase64.
+func DecodeBase64(s string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err == nil {
+		return b, nil
+	}
+	if !strings.HasSuffix(s, "=") {
+		if b, rawErr := base64.RawStdEncoding.DecodeString(s); rawErr == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+}
+
+func canonicalScheme(s string) (string, bool) {
+	for _, known := range knownSchemes {
+		if strings.EqualFold(s, known) {
+			return known, true
+		}
+	}
+	return registeredScheme(s)
+}
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+type parseSchemeCase struct {
+	encoded          string
+	expectedScheme   string
+	expectedHexBytes string
+	expectedErr      error
+}
+
+func TestParseScheme(t *testing.T) {
+	cases := []parseSchemeCase{
+		// SHA-1 of "password"
+		{"{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=", SchemeSHA, "5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8", nil},
+		// salt: "R*w.5Vmo"
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", SchemeSSHA, "87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f", nil},
+		// salt: "R*w.5Vmo", padding stripped
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw", SchemeSSHA, "87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f", nil},
+		// lowercase scheme is canonicalized
+		{"{ssha}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", SchemeSSHA, "87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f", nil},
+		// salt: "R*w.5Vmo"
+		{"{SSHA224}H+JLaQBB5vDLW7R7fltqXBKifY5BGIkesa6NZVIqdy41Vm1v", SchemeSSHA224, "1fe24b690041e6f0cb5bb47b7e5b6a5c12a27d8e4118891eb1ae8d65522a772e35566d6f", nil},
+		// salt: "R*w.5Vmo"
+		{"{SSHA256}TqZvPfsesiz6c5gGffOLZy2BXLjD7Dp31yqT2obq/TtSKncuNVZtbw==", SchemeSSHA256, "4ea66f3dfb1eb22cfa7398067df38b672d815cb8c3ec3a77d72a93da86eafd3b522a772e35566d6f", nil},
+		// salt: "R*w.5Vmo"
+		{"{SSHA384}6MxBeLHC2bHcVYTIHQV7xyG5i5cpXrn2sZ3uihMj0OZoxbJ1PVSmzH2XhbMmhMF7Uip3LjVWbW8=", SchemeSSHA384, "e8cc4178b1c2d9b1dc5584c81d057bc721b98b97295eb9f6b19dee8a1323d0e668c5b2753d54a6cc7d9785b32684c17b522a772e35566d6f", nil},
+		// salt: "R*w.5Vmo"
+		{"{SSHA512}q/vByfpkaHRZTIUPhGP28M+3PLr61NSaVJNf1ACGY7P04iTpvhwHmCGrE2CnFKImeVMwhlN4PsiHA41Ir/gSvFIqdy41Vm1v", SchemeSSHA512, "abfbc1c9fa646874594c850f8463f6f0cfb73cbafad4d49a54935fd4008663b3f4e224e9be1c079821ab1360a714a2267953308653783ec887038d48aff812bc522a772e35566d6f", nil},
+		// bare base64
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", "", "", ErrNoScheme},
+		// unterminated prefix
+		{"{SSHAh+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", "", "", ErrMalformedScheme},
+		// empty prefix
+		{"{}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", "", "", ErrMalformedScheme},
+		// unknown prefix
+		{"{FOO}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", "", "", ErrUnknownScheme},
+		// recognized, but not decodable
+		{"{CRYPT}$6$rounds=5000$saltsalt$hash", "", "", ErrUnsupportedScheme},
+		// garbage base64
+		{"{SSHA}not*valid*base64!", "", "", ErrInvalidBase64},
+		// partial padding
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=", "", "", ErrInvalidBase64},
+	}
+
+	for _, c := range cases {
+		scheme, payload, err := ParseScheme(c.encoded)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("ParseScheme(%q) error = %v; expected %v", c.encoded, err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseScheme(%q) returned unexpected error: %e", c.encoded, err)
+			continue
+		}
+		if scheme != c.expectedScheme {
+			t.Errorf("ParseScheme(%q) scheme = %s; expected %s", c.encoded, scheme, c.expectedScheme)
+		}
+		if result := hex.EncodeToString(payload); result != c.expectedHexBytes {
+			t.Errorf("ParseScheme(%q) payload = %s; expected %s", c.encoded, result, c.expectedHexBytes)
+		}
+	}
+}
+
+func TestParseSchemeCRYPT(t *testing.T) {
+	for _, encoded := range []string{"{CRYPT}$6$rounds=5000$saltsalt$hash", "{crypt}aaX/1htJhjFEw", "{CRYPT}"} {
+		scheme, payload, err := ParseScheme(encoded)
+		if !errors.Is(err, ErrUnsupportedScheme) {
+			t.Errorf("ParseScheme(%q) error = %v; expected %v", encoded, err, ErrUnsupportedScheme)
+		}
+		if errors.Is(err, ErrUnknownScheme) {
+			t.Errorf("ParseScheme(%q) error = %v; expected it not to be %v", encoded, err, ErrUnknownScheme)
+		}
+		if scheme != SchemeCRYPT || payload != nil {
+			t.Errorf("ParseScheme(%q) = %q, %x; expected %q, nil", encoded, scheme, payload, SchemeCRYPT)
+		}
+	}
+
+	if _, _, err := ParseScheme("{CRYPTO}aaX/1htJhjFEw"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("ParseScheme() error = %v; expected %v", err, ErrUnknownScheme)
+	}
+}
+
+type decodeBase64Case struct {
+	encoded          string
+	expectedHexBytes string
+	expectedErr      error
+}
+
+func TestDecodeBase64(t *testing.T) {
+	cases := []decodeBase64Case{
+		{"YWJjZGVmZw==", "61626364656667", nil},
+		{"YWJjZGVmZw", "61626364656667", nil},
+		{"", "", nil},
+		{"YWJjZGVmZw=", "", ErrInvalidBase64},
+		{"not*valid*base64!", "", ErrInvalidBase64},
+	}
+
+	for _, c := range cases {
+		result, err := DecodeBase64(c.encoded)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("DecodeBase64(%q) error = %v; expected %v", c.encoded, err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if hex.EncodeToString(result) != c.expectedHexBytes {
+			t.Errorf("DecodeBase64(%q) = %x; expected %s", c.encoded, result, c.expectedHexBytes)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/kristinjeanna/crypto/smd5
+
+go 1.18
+
+require github.com/kristinjeanna/crypto v1.1.0
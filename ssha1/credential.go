@@ -0,0 +1,76 @@
+package ssha1
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+// Credential is a stored SSHA1 hash that marshals to and from JSON as
+// {"scheme":"{SSHA}","hash":"<base64>"}, for keeping credentials in a
+// document store. The hash is the base-64 encoded SHA-1 digest followed by
+// the salt, without the scheme prefix. The zero value is not a valid
+// Credential; use NewCredential or unmarshal one.
+type Credential struct {
+	hash StoredHash
+}
+
+// credentialJSON is the JSON form of a Credential.
+type credentialJSON struct {
+	Scheme string `json:"scheme"`
+	Hash   string `json:"hash"`
+}
+
+// NewCredential returns the Credential for the specified stored hash.
+func NewCredential(s StoredHash) Credential {
+	return Credential{hash: s}
+}
+
+// StoredHash returns the stored hash of the credential.
+func (c Credential) StoredHash() StoredHash {
+	return c.hash
+}
+
+// Validate returns true if the SSHA1 hash of the sample matches the
+// credential; false, otherwise. The hashes are compared in constant time.
+func (c Credential) Validate(sample []byte) (bool, error) {
+	return c.hash.Validate(sample)
+}
+
+// MarshalJSON encodes the credential as a JSON object with its scheme and
+// base-64 encoded hash. The zero value cannot be marshaled: the error
+// returned by Decode for its empty hash is returned.
+func (c Credential) MarshalJSON() ([]byte, error) { // json.Marshaler interface
+	if _, _, err := Decode(c.hash.ssha1Hash); err != nil {
+		return nil, err
+	}
+	return json.Marshal(credentialJSON{
+		Scheme: scheme,
+		Hash:   base64.StdEncoding.EncodeToString(c.hash.ssha1Hash),
+	})
+}
+
+// UnmarshalJSON decodes a credential encoded by MarshalJSON. A scheme other
+// than "{SSHA}" yields ErrMalformedPrefix; the trailing "=" padding of the
+// hash may be omitted, and the same length rules as for Validate apply.
+func (c *Credential) UnmarshalJSON(b []byte) error { // json.Unmarshaler interface
+	var v credentialJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.Scheme != scheme {
+		return ErrMalformedPrefix
+	}
+
+	ssha1Hash, err := crypto.DecodeBase64(v.Hash)
+	if err != nil {
+		return err
+	}
+	s, err := storedHash(ssha1Hash)
+	if err != nil {
+		return err
+	}
+	c.hash = s
+	return nil
+}
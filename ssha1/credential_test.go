@@ -0,0 +1,105 @@
+package ssha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type credentialCase struct {
+	encoded  string
+	json     string
+	password []byte
+}
+
+func TestCredentialJSON(t *testing.T) {
+	cases := []credentialCase{
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", `{"scheme":"{SSHA}","hash":"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="}`, []byte("You have to be odd to be number one.")},
+		{"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", `{"scheme":"{SSHA}","hash":"hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn"}`, []byte("1234567890")},
+		{"{SSHA}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==", `{"scheme":"{SSHA}","hash":"KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ=="}`, []byte("When life gives you lemons, make lemonade.")},
+	}
+
+	for _, c := range cases {
+		s, err := ParseStoredHash(c.encoded)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+
+		b, err := json.Marshal(NewCredential(s))
+		if err != nil {
+			t.Errorf("method MarshalJSON() returned unexpected error: %e", err)
+			continue
+		}
+		if string(b) != c.json {
+			t.Errorf("MarshalJSON result = %s; expected %s", b, c.json)
+		}
+
+		var cred Credential
+		if err := json.Unmarshal(b, &cred); err != nil {
+			t.Errorf("method UnmarshalJSON() returned unexpected error: %e", err)
+			continue
+		}
+		if !bytes.Equal(cred.StoredHash().Bytes(), s.Bytes()) {
+			t.Errorf("UnmarshalJSON hash = %x; expected %x", cred.StoredHash().Bytes(), s.Bytes())
+		}
+
+		if result, err := cred.Validate(c.password); err != nil || !result {
+			t.Errorf("Validate(%q) = %t, %v; expected true, nil", c.password, result, err)
+		}
+		if result, err := cred.Validate(append(c.password, '!')); err != nil || result {
+			t.Errorf("Validate(%q) = %t, %v; expected false, nil", append(c.password, '!'), result, err)
+		}
+	}
+}
+
+func TestCredentialInStruct(t *testing.T) {
+	type user struct {
+		Name       string     `json:"name"`
+		Credential Credential `json:"credential"`
+	}
+
+	doc := `{"name":"alice","credential":{"scheme":"{SSHA}","hash":"hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn"}}`
+	var u user
+	if err := json.Unmarshal([]byte(doc), &u); err != nil {
+		t.Errorf("method UnmarshalJSON() returned unexpected error: %e", err)
+	}
+	if result, err := u.Credential.Validate([]byte("1234567890")); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Errorf("method MarshalJSON() returned unexpected error: %e", err)
+	}
+	if string(b) != doc {
+		t.Errorf("MarshalJSON result = %s; expected %s", b, doc)
+	}
+}
+
+type credentialErrorCase struct {
+	json     string
+	expected error
+}
+
+func TestCredentialJSONErrors(t *testing.T) {
+	cases := []credentialErrorCase{
+		{`{"scheme":"{SSHA256}","hash":"hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn"}`, ErrMalformedPrefix},
+		{`{"hash":"hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn"}`, ErrMalformedPrefix},
+		{`{"scheme":"{SSHA}","hash":"hBdoDAlkTfdD186h!m++E6MbLV5hYmNkZWZn"}`, ErrInvalidBase64},
+		{`{"scheme":"{SSHA}","hash":"W6ph5Mm5Pz8GgiULbPgzG37mj9g="}`, ErrSliceTooShortSSHA1},
+		{`{"scheme":"{SSHA}","hash":""}`, ErrSliceTooShortSHA1},
+	}
+
+	for _, c := range cases {
+		var cred Credential
+		if err := json.Unmarshal([]byte(c.json), &cred); !errors.Is(err, c.expected) {
+			t.Errorf("UnmarshalJSON(%s) error = %v; expected %v", c.json, err, c.expected)
+		}
+	}
+
+	if _, err := json.Marshal(Credential{}); !errors.Is(err, ErrSliceTooShortSHA1) {
+		t.Errorf("MarshalJSON() of the zero value error = %v; expected %v", err, ErrSliceTooShortSHA1)
+	}
+}
@@ -0,0 +1,77 @@
+package ssha1
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+const base64Chars string = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// Errors returned by CryptString and ValidateCryptString.
+var (
+	// ErrInvalidDelimiter is returned when the delimiter could appear in
+	// base-64 encoded data.
+	ErrInvalidDelimiter = errors.New("invalid delimiter, must not be a base64 character")
+
+	// ErrMalformedCrypt is returned when a delimited hash does not consist
+	// of a base-64 encoded SHA-1 digest and salt separated by the
+	// delimiter.
+	ErrMalformedCrypt = errors.New("malformed delimited hash, expected base64(sha1) delim base64(salt)")
+)
+
+// CryptString returns the sum of h in the delimited form
+// base64(sha1) || delim || base64(salt), as expected by some tooling for
+// shadow-file-like formats, e.g. "<digest>$<salt>" for a delim of '$'.
+// This is a different layout than the "{SSHA}" form returned by String,
+// in which the SHA-1 digest and salt are concatenated before encoding, and
+// the two must not be mixed. The delimiter must not be a base-64
+// character.
+func CryptString(h crypto.Hash, delim byte) (string, error) {
+	if strings.IndexByte(base64Chars, delim) >= 0 {
+		return "", ErrInvalidDelimiter
+	}
+
+	sum := h.Sum(nil)
+	n := len(sum) - h.SaltSize()
+	sha1Part, salt := sum[:n], sum[n:]
+	if d, ok := h.(*digest); ok && d.pos == SaltPrefix {
+		salt, sha1Part = sum[:h.SaltSize()], sum[h.SaltSize():]
+	}
+
+	return base64.StdEncoding.EncodeToString(sha1Part) + string(delim) +
+		base64.StdEncoding.EncodeToString(salt), nil
+}
+
+// ValidateCryptString returns true if the SSHA1 hash of the sample matches
+// the specified hash in the delimited form produced by CryptString; false,
+// otherwise. As for Validate, the salt is taken to follow the data when
+// hashing. The hashes are compared in constant time.
+func ValidateCryptString(encoded string, delim byte, sample []byte) (bool, error) {
+	if strings.IndexByte(base64Chars, delim) >= 0 {
+		return false, ErrInvalidDelimiter
+	}
+
+	encodedSHA1, encodedSalt, found := strings.Cut(encoded, string(delim))
+	if !found {
+		return false, ErrMalformedCrypt
+	}
+
+	sha1Part, err := crypto.DecodeBase64(encodedSHA1)
+	if err != nil {
+		return false, err
+	}
+	if len(sha1Part) != sha1.Size {
+		return false, ErrMalformedCrypt
+	}
+
+	salt, err := crypto.DecodeBase64(encodedSalt)
+	if err != nil {
+		return false, err
+	}
+
+	return Validate(append(sha1Part, salt...), sample)
+}
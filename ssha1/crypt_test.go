@@ -0,0 +1,106 @@
+package ssha1
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCryptString(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("You have to be odd to be number one."))
+
+	expected := "h+WWKpgLY/OQorn+uHAi7Gsr9LY=$Uip3LjVWbW8="
+	result, err := CryptString(c, '$')
+	if err != nil {
+		t.Errorf("method CryptString() returned unexpected error: %e", err)
+	}
+	if result != expected {
+		t.Errorf("CryptString result = %s; expected %s", result, expected)
+	}
+
+	// round trip
+	if ok, err := ValidateCryptString(result, '$', []byte("You have to be odd to be number one.")); err != nil || !ok {
+		t.Errorf("ValidateCryptString() = %t, %v; expected true, nil", ok, err)
+	}
+	if ok, err := ValidateCryptString(result, '$', []byte("You have to be odd to be number two.")); err != nil || ok {
+		t.Errorf("ValidateCryptString() = %t, %v; expected false, nil", ok, err)
+	}
+
+	// the delimited form is not interchangeable with the "{SSHA}" form
+	if _, err := ValidateString(result, []byte("You have to be odd to be number one.")); err == nil {
+		t.Errorf("ValidateString() accepted delimited form %s", result)
+	}
+}
+
+func TestCryptStringSaltPrefix(t *testing.T) {
+	c, err := NewWithSaltPosition([]byte("R*w.5Vmo"), SaltPrefix)
+	if err != nil {
+		t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("You have to be odd to be number one."))
+
+	// SHA1(salt || data) $ salt
+	expected := "7da4Vp/0lsSFdO3sidpO9fn6M6M=$Uip3LjVWbW8="
+	if result, err := CryptString(c, '$'); err != nil || result != expected {
+		t.Errorf("CryptString() = %s, %v; expected %s, nil", result, err, expected)
+	}
+}
+
+type cryptCase struct {
+	encoded     string
+	delim       byte
+	sample      []byte
+	expected    bool
+	expectedErr error
+}
+
+func TestValidateCryptString(t *testing.T) {
+	cases := []cryptCase{
+		// salt: "R*w.5Vmo"
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=$Uip3LjVWbW8=", '$', []byte("You have to be odd to be number one."), true, nil},
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=:Uip3LjVWbW8=", ':', []byte("You have to be odd to be number one."), true, nil},
+		// padding stripped
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY$Uip3LjVWbW8", '$', []byte("You have to be odd to be number one."), true, nil},
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=$Uip3LjVWbW8=", '$', []byte("You have to be odd to be number two."), false, nil},
+		// wrong delimiter
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=$Uip3LjVWbW8=", ':', nil, false, ErrMalformedCrypt},
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=$Uip3LjVWbW8=", '+', nil, false, ErrInvalidDelimiter},
+		// digest too short
+		{"UgpBsp+JG7rM8x0=$Uip3LjVWbW8=", '$', nil, false, ErrMalformedCrypt},
+		// no salt
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=$", '$', nil, false, ErrSliceTooShortSSHA1},
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LY=$not*base64", '$', nil, false, ErrInvalidBase64},
+		{"not*base64$Uip3LjVWbW8=", '$', nil, false, ErrInvalidBase64},
+	}
+
+	for _, c := range cases {
+		result, err := ValidateCryptString(c.encoded, c.delim, c.sample)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("ValidateCryptString(%q) error = %v; expected %v", c.encoded, err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("validation test failed for test case %v", c)
+		}
+	}
+}
+
+func TestCryptStringInvalidDelimiter(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	for _, delim := range []byte{'A', 'z', '0', '+', '/', '='} {
+		if _, err := CryptString(c, delim); !errors.Is(err, ErrInvalidDelimiter) {
+			t.Errorf("CryptString(%q) error = %v; expected %v", delim, err, ErrInvalidDelimiter)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package ssha1
+
+import (
+	"bytes"
+	"crypto/sha1"
+)
+
+// ValidateReport describes the outcome of ValidateVerbose segment by
+// segment.
+type ValidateReport struct {
+	// DigestMatch is true if the SHA-1 segment of the recomputed hash
+	// matches that of the stored hash. As the salt is taken from the
+	// stored hash, a corrupted salt shows up as a digest mismatch too.
+	DigestMatch bool
+
+	// Salt is a copy of the salt taken from the stored hash, i.e. the salt
+	// that was used to recompute the hash.
+	Salt []byte
+
+	// StoredDigest and ComputedDigest are copies of the SHA-1 segments of
+	// the stored and recomputed hashes.
+	StoredDigest, ComputedDigest []byte
+}
+
+// ValidateVerbose is like Validate, but also returns a report of the
+// segments of the stored and recomputed hashes, to help track down validation
+// failures during development.
+//
+// ValidateVerbose is NOT timing-safe: the digests are compared with
+// bytes.Equal, and the report itself reveals the expected digest. It must
+// not be used in production code or wherever the sample is a secret
+// supplied by an untrusted party; use Validate instead.
+func ValidateVerbose(ssha1Hash, sample []byte) (ok bool, report ValidateReport, err error) {
+	storedDigest, salt, err := Decode(ssha1Hash)
+	if err != nil {
+		return false, ValidateReport{}, err
+	}
+
+	d, err := NewWithSalt(salt)
+	if err != nil {
+		return false, ValidateReport{}, err
+	}
+	d.Write(sample)
+	result := d.Sum(nil)
+	computedDigest := result[:sha1.Size]
+
+	report = ValidateReport{
+		DigestMatch:    bytes.Equal(storedDigest, computedDigest),
+		Salt:           append([]byte(nil), salt...),
+		StoredDigest:   append([]byte(nil), storedDigest...),
+		ComputedDigest: computedDigest,
+	}
+	return report.DigestMatch, report, nil
+}
@@ -0,0 +1,73 @@
+package ssha1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestValidateVerbose(t *testing.T) {
+	// salt: "abcdefg"
+	stored, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+
+	ok, report, err := ValidateVerbose(stored, []byte("1234567890"))
+	if err != nil {
+		t.Errorf("method ValidateVerbose() returned unexpected error: %e", err)
+	}
+	if !ok || !report.DigestMatch {
+		t.Errorf("ValidateVerbose() = %t, %+v; expected a full match", ok, report)
+	}
+	if !bytes.Equal(report.StoredDigest, report.ComputedDigest) {
+		t.Errorf("StoredDigest = %x; expected ComputedDigest %x", report.StoredDigest, report.ComputedDigest)
+	}
+
+	// wrong password
+	ok, report, err = ValidateVerbose(stored, []byte("123456789"))
+	if err != nil {
+		t.Errorf("method ValidateVerbose() returned unexpected error: %e", err)
+	}
+	if ok || report.DigestMatch {
+		t.Errorf("ValidateVerbose() = %t, %+v; expected a digest mismatch only", ok, report)
+	}
+	if !bytes.Equal(report.Salt, []byte("abcdefg")) {
+		t.Errorf("Salt = %q; expected %q", report.Salt, "abcdefg")
+	}
+
+	// corrupted salt
+	corrupted := append([]byte(nil), stored...)
+	corrupted[len(corrupted)-1] = 'x'
+	ok, report, err = ValidateVerbose(corrupted, []byte("1234567890"))
+	if err != nil {
+		t.Errorf("method ValidateVerbose() returned unexpected error: %e", err)
+	}
+	if ok || report.DigestMatch {
+		t.Errorf("ValidateVerbose() = %t, %+v; expected a digest mismatch", ok, report)
+	}
+	if !bytes.Equal(report.Salt, []byte("abcdefx")) {
+		t.Errorf("Salt = %q; expected the corrupted salt %q", report.Salt, "abcdefx")
+	}
+	if !bytes.Equal(report.StoredDigest, stored[:20]) {
+		t.Errorf("StoredDigest = %x; expected %x", report.StoredDigest, stored[:20])
+	}
+
+	// agrees with Validate
+	for _, sample := range [][]byte{[]byte("1234567890"), []byte("123456789")} {
+		expected, _ := Validate(stored, sample)
+		if ok, _, _ := ValidateVerbose(stored, sample); ok != expected {
+			t.Errorf("ValidateVerbose(%q) = %t; Validate returned %t", sample, ok, expected)
+		}
+	}
+}
+
+func TestValidateVerboseErrors(t *testing.T) {
+	if _, _, err := ValidateVerbose(make([]byte, 19), nil); !errors.Is(err, ErrSliceTooShortSHA1) {
+		t.Errorf("ValidateVerbose() error = %v; expected %v", err, ErrSliceTooShortSHA1)
+	}
+	if _, _, err := ValidateVerbose(make([]byte, 20), nil); !errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("ValidateVerbose() error = %v; expected %v", err, ErrSliceTooShortSSHA1)
+	}
+}
@@ -0,0 +1,43 @@
+package ssha1
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// DeprecationEnv is the environment variable that, when set to a non-empty
+// value, suppresses the deprecation notice printed by New and Sum.
+const DeprecationEnv string = "SSHA1_NO_DEPRECATION_NOTICE"
+
+const deprecationNotice string = "ssha1: SHA-1 is not recommended for new password hashes; " +
+	"use the ssha256 or ssha512 package instead (set " + DeprecationEnv + "=1 to silence this notice)"
+
+var (
+	deprecationOnce       sync.Once
+	deprecationSuppressed int32
+	deprecationOutput     io.Writer = os.Stderr
+)
+
+// SuppressDeprecationNotice suppresses the notice, printed to standard
+// error the first time New or Sum is called, that SHA-1 should not be
+// picked for new hashes. Programs that use SSHA1 knowingly, e.g. for
+// interop with an existing directory, can call it during initialization;
+// alternatively, DeprecationEnv can be set.
+func SuppressDeprecationNotice() {
+	atomic.StoreInt32(&deprecationSuppressed, 1)
+}
+
+// noteDeprecation prints the deprecation notice, at most once per process
+// and only if it has not been suppressed. After the first call it costs no
+// more than the check of a sync.Once.
+func noteDeprecation() {
+	deprecationOnce.Do(func() {
+		if atomic.LoadInt32(&deprecationSuppressed) != 0 || os.Getenv(DeprecationEnv) != "" {
+			return
+		}
+		fmt.Fprintln(deprecationOutput, deprecationNotice)
+	})
+}
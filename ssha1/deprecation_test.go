@@ -0,0 +1,75 @@
+package ssha1
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestMain discards the deprecation notice, so that test output does not
+// depend on which test happens to call New or Sum first. The tests of the
+// notice itself capture it via resetDeprecation.
+func TestMain(m *testing.M) {
+	deprecationOutput = io.Discard
+	os.Exit(m.Run())
+}
+
+// resetDeprecation rearms the deprecation notice, capturing it in the
+// returned buffer, and restores the original state when the test ends.
+func resetDeprecation(t *testing.T) *bytes.Buffer {
+	out := deprecationOutput
+	suppressed := atomic.LoadInt32(&deprecationSuppressed)
+	t.Cleanup(func() {
+		deprecationOutput = out
+		atomic.StoreInt32(&deprecationSuppressed, suppressed)
+	})
+
+	var buf bytes.Buffer
+	deprecationOnce = sync.Once{}
+	deprecationOutput = &buf
+	atomic.StoreInt32(&deprecationSuppressed, 0)
+	return &buf
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	t.Setenv(DeprecationEnv, "")
+	buf := resetDeprecation(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := New(); err != nil {
+			t.Errorf("method New() returned unexpected error: %e", err)
+		}
+		if _, err := Sum([]byte("data"), []byte("salt")); err != nil {
+			t.Errorf("method Sum() returned unexpected error: %e", err)
+		}
+	}
+
+	if n := strings.Count(buf.String(), deprecationNotice); n != 1 {
+		t.Errorf("notice printed %d times; expected once: %q", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), "ssha256") {
+		t.Errorf("notice %q does not point to ssha256", buf.String())
+	}
+}
+
+func TestDeprecationNoticeSuppressed(t *testing.T) {
+	t.Setenv(DeprecationEnv, "")
+	buf := resetDeprecation(t)
+	SuppressDeprecationNotice()
+	New()
+	Sum([]byte("data"), []byte("salt"))
+	if buf.Len() != 0 {
+		t.Errorf("suppressed notice printed: %q", buf.String())
+	}
+
+	t.Setenv(DeprecationEnv, "1")
+	buf = resetDeprecation(t)
+	New()
+	if buf.Len() != 0 {
+		t.Errorf("notice printed despite %s: %q", DeprecationEnv, buf.String())
+	}
+}
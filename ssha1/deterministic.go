@@ -0,0 +1,20 @@
+package ssha1
+
+import (
+	"math/rand"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+// NewDeterministic returns a new hash.Hash with a salt of the specified
+// size derived from a math/rand source seeded with seed, so that the same
+// seed always yields the same salt. Salt size must be between 1 and 1024
+// bytes.
+//
+// NewDeterministic is meant for tests that need to assert exact output,
+// e.g. of String. The salt is predictable, so it must NOT be used for
+// hashes that are stored or otherwise used in production; use New or
+// NewForSaltSize instead.
+func NewDeterministic(seed int64, numSaltBytes int) (crypto.Hash, error) {
+	return NewWithRand(rand.New(rand.NewSource(seed)), numSaltBytes)
+}
@@ -0,0 +1,54 @@
+package ssha1
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+func TestNewDeterministic(t *testing.T) {
+	plaintext := []byte("All that glitters is not gold.")
+
+	for _, size := range []int{1, 8, DefaultNumSaltBytes, MaxSaltBytes} {
+		a, err := NewDeterministic(42, size)
+		if err != nil {
+			t.Errorf("method NewDeterministic() returned unexpected error: %e", err)
+			continue
+		}
+		b, err := NewDeterministic(42, size)
+		if err != nil {
+			t.Errorf("method NewDeterministic() returned unexpected error: %e", err)
+			continue
+		}
+		a.Write(plaintext)
+		b.Write(plaintext)
+
+		if a.SaltSize() != size {
+			t.Errorf("SaltSize() = %d; expected %d", a.SaltSize(), size)
+		}
+		if !bytes.Equal(a.(crypto.Salter).Salt(), b.(crypto.Salter).Salt()) {
+			t.Errorf("salts differ for the same seed: %x and %x", a.(crypto.Salter).Salt(), b.(crypto.Salter).Salt())
+		}
+		if a.String() != b.String() {
+			t.Errorf("String() differs for the same seed: %s and %s", a.String(), b.String())
+		}
+	}
+
+	a, err := NewDeterministic(1, 16)
+	if err != nil {
+		t.Errorf("method NewDeterministic() returned unexpected error: %e", err)
+	}
+	b, err := NewDeterministic(2, 16)
+	if err != nil {
+		t.Errorf("method NewDeterministic() returned unexpected error: %e", err)
+	}
+	if bytes.Equal(a.(crypto.Salter).Salt(), b.(crypto.Salter).Salt()) {
+		t.Errorf("salts equal for different seeds: %x", a.(crypto.Salter).Salt())
+	}
+
+	if _, err := NewDeterministic(42, 0); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewDeterministic() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
@@ -25,6 +25,12 @@ Likewise, to validate a hash:
 		fmt.Println("validation failed")
 	}
 
+Hashes in the base-64 encoded form produced by String(), such as those
+stored in an LDAP directory, can be validated with ValidateString(). The
+"{SSHA}" prefix is optional:
+
+	result, err := ValidateString("{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", plaintext)
+
 As an alternative, you can use the provided hash.Hash implementation. The
 NewXxx functions allow you to create instances.
 
@@ -42,7 +48,38 @@ salt (via the crypto/rand package) of a specified size:
 
 	h, err := NewForSaltSize(32)
 
-Note that the minimum salt size permitted is 1 byte.
+As with the hash.Hash implementations in the standard library, Sum() does
+not finalize the hash. Data written after a call to Sum() extends the data
+already written, so a running sum can be taken mid-stream:
+
+	h.Write([]byte("part one"))
+	partial := h.Sum(nil) // sum of "part one"
+	h.Write([]byte(", part two"))
+	full := h.Sum(nil) // sum of "part one, part two"
+
+The "{SSHA}" format, base64(SHA1(plaintext || salt) || salt), is the one
+used by OpenLDAP and by Python's passlib (ldap_salted_sha1), and hashes
+created by either validate with ValidateString(); the tests include the
+reference hashes of passlib, but no slappasswd output. passlib is stricter
+about the salt size, only accepting 4 to 16 bytes, so hashes meant to be
+verified by passlib must be created with e.g. NewForSaltSize(16) rather than
+the default of 20 bytes.
+
+Appliances whose directory service is built on OpenLDAP, such as the LDAP
+Server package of Synology NAS devices, are expected to store the same
+format; no appliance-generated hashes are available to the tests, so this
+is unconfirmed. When such hashes fail to validate, check how they were
+exported: an LDIF "userPassword::" line holds the base-64 encoding of the
+whole "{SSHA}..." string, which must be decoded first.
+
+SHA-1 is no longer considered secure, and SSHA1 should only be chosen for
+interop with existing hashes. To keep it from being picked unknowingly,
+the first call to New() or Sum() prints a notice to standard error pointing
+to the ssha256 and ssha512 packages. Call SuppressDeprecationNotice() or
+set the SSHA1_NO_DEPRECATION_NOTICE environment variable to silence it.
+
+Note that the minimum salt size permitted is 1 byte and the maximum is
+1024 bytes.
 
 */
 package ssha1
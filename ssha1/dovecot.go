@@ -0,0 +1,36 @@
+package ssha1
+
+import (
+	"strings"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+const hexScheme string = "{SSHA.HEX}"
+
+// DovecotHexString returns the hex encoded representation of the sum of h,
+// prefixed with "{SSHA.HEX}", as used by Dovecot. The byte layout is the
+// same as for "{SSHA}"; only the encoding differs.
+func DovecotHexString(h crypto.Hash) string {
+	return hexScheme + h.HexString()
+}
+
+// VerifyDovecot returns true if password matches the stored Dovecot hash;
+// false, otherwise. Both the base-64 "{SSHA}" and the hex "{SSHA.HEX}"
+// schemes are supported and detected from the prefix, which Dovecot
+// treats case-insensitively. Any other prefix is rejected with
+// ErrMalformedPrefix.
+func VerifyDovecot(stored string, password []byte) (bool, error) {
+	switch {
+	case hasSchemeFold(stored, hexScheme):
+		return HexValidate(stored[len(hexScheme):], password)
+	case hasSchemeFold(stored, scheme):
+		return ValidateString(scheme+stored[len(scheme):], password)
+	default:
+		return false, ErrMalformedPrefix
+	}
+}
+
+func hasSchemeFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,63 @@
+package ssha1
+
+import (
+	"errors"
+	"testing"
+)
+
+// No doveadm pw output is available to these tests, so the vectors are
+// self-generated: they are the baseline vectors of TestHexString and
+// TestValidateString under Dovecot's scheme names, cross-checked against
+// SHA-1 from Python's hashlib rather than against Dovecot itself.
+
+func TestDovecotHexString(t *testing.T) {
+	c, err := NewWithSalt([]byte("ajE94aZM"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("When life gives you lemons, make lemonade."))
+
+	expected := "{SSHA.HEX}294ac58b8b662e8f604fcf6ea4ca01105d580083616a453934615a4d"
+	if result := DovecotHexString(c); result != expected {
+		t.Errorf("DovecotHexString result = %s; expected %s", result, expected)
+	}
+}
+
+type dovecotCase struct {
+	stored      string
+	password    []byte
+	expected    bool
+	expectedErr error
+}
+
+func TestVerifyDovecot(t *testing.T) {
+	cases := []dovecotCase{
+		// the same credential in both encodings, salt: "ajE94aZM"
+		{"{SSHA}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==", []byte("When life gives you lemons, make lemonade."), true, nil},
+		{"{SSHA.HEX}294ac58b8b662e8f604fcf6ea4ca01105d580083616a453934615a4d", []byte("When life gives you lemons, make lemonade."), true, nil},
+		{"{ssha.hex}294AC58B8B662E8F604FCF6EA4CA01105D580083616A453934615A4D", []byte("When life gives you lemons, make lemonade."), true, nil},
+		{"{ssha}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==", []byte("When life gives you lemons, make lemonade."), true, nil},
+		{"{SSHA.HEX}294ac58b8b662e8f604fcf6ea4ca01105d580083616a453934615a4d", []byte("When life gives you limes, make limeade."), false, nil},
+		// salt: "abcdefg"
+		{"{SSHA.HEX}8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667", []byte("1234567890"), true, nil},
+		{"{SSHA.HEX}not-hex", nil, false, ErrInvalidHex},
+		{"{SSHA.HEX}8417680c0", nil, false, ErrInvalidHex},
+		{"{SSHA.HEX}9ab50f27d4201db9b28483ba83c48ebafbb2aa17", nil, false, ErrSliceTooShortSSHA1},
+		{"{PLAIN}password", []byte("password"), false, ErrMalformedPrefix},
+	}
+
+	for _, c := range cases {
+		result, err := VerifyDovecot(c.stored, c.password)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("VerifyDovecot(%q) error = %v; expected %v", c.stored, err, c.expectedErr)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("verification test failed for test case %v", c)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ssha1
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+// Encoder encodes and validates SSHA1 hashes with a fixed base-64 variant
+// and scheme prefix, so that the choice is made once rather than at every
+// call site.
+type Encoder struct {
+	enc    *base64.Encoding
+	prefix string
+}
+
+// NewEncoder returns an Encoder using enc, e.g. base64.StdEncoding or
+// base64.URLEncoding, and the specified prefix, e.g. "{SSHA}". The prefix
+// may be empty. If enc is nil, base64.StdEncoding is used.
+func NewEncoder(enc *base64.Encoding, prefix string) *Encoder {
+	if enc == nil {
+		enc = base64.StdEncoding
+	}
+	return &Encoder{enc: enc, prefix: prefix}
+}
+
+// Encode returns the sum of h, base-64 encoded with the encoder's variant
+// and prefixed with its prefix.
+func (e *Encoder) Encode(h crypto.Hash) string {
+	return e.prefix + e.enc.EncodeToString(h.Sum(nil))
+}
+
+// Validate returns true if the SSHA1 hash of the sample matches the
+// specified hash, as produced by Encode; false, otherwise. The encoded hash
+// must carry the encoder's prefix, otherwise ErrMalformedPrefix is
+// returned, and must be encoded with its base-64 variant.
+func (e *Encoder) Validate(encoded string, sample []byte) (bool, error) {
+	if !strings.HasPrefix(encoded, e.prefix) {
+		return false, ErrMalformedPrefix
+	}
+
+	ssha1Hash, err := e.enc.DecodeString(encoded[len(e.prefix):])
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+	}
+	return Validate(ssha1Hash, sample)
+}
@@ -0,0 +1,71 @@
+package ssha1
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+type encoderCase struct {
+	enc      *base64.Encoding
+	prefix   string
+	expected string
+}
+
+func TestEncoder(t *testing.T) {
+	// salt: "R*w.5Vmo"
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	plaintext := []byte("You have to be odd to be number one.")
+	c.Write(plaintext)
+
+	cases := []encoderCase{
+		{base64.StdEncoding, scheme, "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="},
+		{base64.URLEncoding, scheme, "{SSHA}h-WWKpgLY_OQorn-uHAi7Gsr9LZSKncuNVZtbw=="},
+		{base64.RawURLEncoding, "", "h-WWKpgLY_OQorn-uHAi7Gsr9LZSKncuNVZtbw"},
+		{nil, "{ssha}", "{ssha}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="},
+	}
+
+	for _, tc := range cases {
+		e := NewEncoder(tc.enc, tc.prefix)
+
+		encoded := e.Encode(c)
+		if encoded != tc.expected {
+			t.Errorf("Encode() = %s; expected %s", encoded, tc.expected)
+		}
+
+		if result, err := e.Validate(encoded, plaintext); err != nil || !result {
+			t.Errorf("Validate(%q) = %t, %v; expected true, nil", encoded, result, err)
+		}
+		if result, err := e.Validate(encoded, plaintext[1:]); err != nil || result {
+			t.Errorf("Validate(%q) = %t, %v; expected false, nil", encoded, result, err)
+		}
+	}
+
+	if c.(crypto.URLStringer).URLString() != NewEncoder(base64.URLEncoding, scheme).Encode(c) {
+		t.Errorf("Encode() with URLEncoding differs from URLString()")
+	}
+}
+
+func TestEncoderErrors(t *testing.T) {
+	std := NewEncoder(base64.StdEncoding, scheme)
+	url := NewEncoder(base64.URLEncoding, scheme)
+
+	if _, err := std.Validate("h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil); !errors.Is(err, ErrMalformedPrefix) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrMalformedPrefix)
+	}
+	// the variants must not be mixed
+	if _, err := url.Validate("{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil); !errors.Is(err, ErrInvalidBase64) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrInvalidBase64)
+	}
+	if _, err := std.Validate("{SSHA}h-WWKpgLY_OQorn-uHAi7Gsr9LZSKncuNVZtbw==", nil); !errors.Is(err, ErrInvalidBase64) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrInvalidBase64)
+	}
+	if _, err := std.Validate("{SSHA}mrUPJ9QgHbmyhIO6g8SOuvuyqhc=", nil); !errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSliceTooShortSSHA1)
+	}
+}
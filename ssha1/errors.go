@@ -0,0 +1,53 @@
+package ssha1
+
+import "errors"
+
+// saltErrors are the errors reported by IsSaltError.
+var saltErrors = []error{
+	ErrNilSalt,
+	ErrSaltTooShort,
+	ErrSaltTooLong,
+	ErrWeakSalt,
+	ErrSaltBelowPolicy,
+	ErrSliceTooShortSalt,
+	ErrInvalidSaltPosition,
+	ErrSaltAlreadyHashed,
+}
+
+// lengthErrors are the errors reported by IsLengthError.
+var lengthErrors = []error{
+	ErrSaltTooShort,
+	ErrSaltTooLong,
+	ErrSaltBelowPolicy,
+	ErrSliceTooShortSHA1,
+	ErrSliceTooShortSSHA1,
+	ErrSliceTooShortSalt,
+	ErrInvalidSHA1Size,
+	ErrInputTooLarge,
+}
+
+// IsSaltError reports whether err is, or wraps, an error about a salt:
+// a missing, weak or wrongly sized salt, or one that cannot be used as
+// requested. Callers can branch on the category without enumerating its
+// errors, which may grow over time.
+func IsSaltError(err error) bool {
+	return isAny(err, saltErrors)
+}
+
+// IsLengthError reports whether err is, or wraps, an error about the
+// length of an input: a hash, digest or salt that is too short or too
+// long, or input exceeding a limit set by SetMaxInput. Errors about salt
+// sizes are both salt and length errors.
+func IsLengthError(err error) bool {
+	return isAny(err, lengthErrors)
+}
+
+// isAny reports whether errors.Is(err, target) holds for any of targets.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
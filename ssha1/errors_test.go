@@ -0,0 +1,89 @@
+package ssha1
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+type errorCategoryCase struct {
+	err      error
+	isSalt   bool
+	isLength bool
+}
+
+func TestErrorCategories(t *testing.T) {
+	cases := []errorCategoryCase{
+		{ErrNilSalt, true, false},
+		{ErrSaltTooShort, true, true},
+		{ErrSaltTooLong, true, true},
+		{ErrWeakSalt, true, false},
+		{ErrSaltBelowPolicy, true, true},
+		{ErrSliceTooShortSalt, true, true},
+		{ErrInvalidSaltPosition, true, false},
+		{ErrSaltAlreadyHashed, true, false},
+		{ErrSliceTooShortSHA1, false, true},
+		{ErrSliceTooShortSSHA1, false, true},
+		{ErrInvalidSHA1Size, false, true},
+		{ErrInputTooLarge, false, true},
+		{ErrMalformedPrefix, false, false},
+		{ErrInvalidBase64, false, false},
+		{ErrInvalidHex, false, false},
+		{ErrEncodedHash, false, false},
+		{ErrInvalidState, false, false},
+		{ErrUnsupportedStateVersion, false, false},
+		{ErrInvalidDelimiter, false, false},
+		{ErrMalformedCrypt, false, false},
+		{ErrMalformedPHC, false, false},
+		{ErrEmptyPepper, false, false},
+		{ErrPepperedState, false, false},
+		{errors.New("unrelated"), false, false},
+		{nil, false, false},
+	}
+
+	for _, c := range cases {
+		if result := IsSaltError(c.err); result != c.isSalt {
+			t.Errorf("IsSaltError(%v) = %t; expected %t", c.err, result, c.isSalt)
+		}
+		if result := IsLengthError(c.err); result != c.isLength {
+			t.Errorf("IsLengthError(%v) = %t; expected %t", c.err, result, c.isLength)
+		}
+
+		// wrapped errors are classified alike
+		if c.err == nil {
+			continue
+		}
+		wrapped := fmt.Errorf("context: %w", c.err)
+		if IsSaltError(wrapped) != c.isSalt || IsLengthError(wrapped) != c.isLength {
+			t.Errorf("wrapped %v classified differently", c.err)
+		}
+	}
+
+	// errors as returned by the package
+	if _, _, err := Decode(make([]byte, 5)); !IsLengthError(err) {
+		t.Errorf("IsLengthError(%v) = false; expected true", err)
+	}
+	if _, err := NewWithSalt(nil); !IsSaltError(err) {
+		t.Errorf("IsSaltError(%v) = false; expected true", err)
+	}
+}
+
+func TestSharedErrors(t *testing.T) {
+	// the salt and base-64 errors are those of the crypto package, as for
+	// the other schemes, so that they can be checked for once
+	if _, err := NewWithSalt(nil); !errors.Is(err, crypto.ErrNilSalt) {
+		t.Errorf("NewWithSalt(nil) error = %v; expected %v", err, crypto.ErrNilSalt)
+	}
+	if _, err := NewWithSalt([]byte{}); !errors.Is(err, crypto.ErrSaltTooShort) {
+		t.Errorf("NewWithSalt() error = %v; expected %v", err, crypto.ErrSaltTooShort)
+	}
+	if _, err := NewWithSalt(make([]byte, MaxSaltBytes+1)); !errors.Is(err, crypto.ErrSaltTooLong) {
+		t.Errorf("NewWithSalt() error = %v; expected %v", err, crypto.ErrSaltTooLong)
+	}
+
+	if _, err := ValidateString("{SSHA}not*valid*base64!", nil); !errors.Is(err, crypto.ErrInvalidBase64) {
+		t.Errorf("ValidateString() error = %v; expected %v", err, crypto.ErrInvalidBase64)
+	}
+}
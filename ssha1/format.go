@@ -0,0 +1,167 @@
+package ssha1
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Encoding specifies how Format encodes the SHA-1 digest and the salt.
+type Encoding int
+
+const (
+	// EncodingBase64 is the standard, padded base-64 encoding used by
+	// String. It is the default.
+	EncodingBase64 Encoding = iota
+
+	// EncodingBase64URL is the URL and filename safe, padded base-64
+	// encoding used by URLString.
+	EncodingBase64URL
+
+	// EncodingHex is the lowercase hexadecimal encoding used by HexString.
+	EncodingHex
+)
+
+// ErrMalformedFormat is returned by ParseFormat when the encoded SHA-1
+// digest is truncated or not followed by the delimiter.
+var ErrMalformedFormat = errors.New("malformed hash, expected the encoded digest followed by the delimiter")
+
+// FormatOptions specifies how Format lays out the sum. The zero value
+// reproduces String.
+type FormatOptions struct {
+	// HashEncoding is the encoding of the SHA-1 digest.
+	HashEncoding Encoding
+
+	// SaltEncoding is the encoding of the salt.
+	SaltEncoding Encoding
+
+	// Delimiter separates the encoded digest from the encoded salt. If it
+	// is empty and both encodings are the same, the digest and salt are
+	// encoded together as a whole, as String does; otherwise, they are
+	// encoded separately and joined by the delimiter.
+	Delimiter string
+
+	// Prefix is prepended to the output. If empty, "{SSHA}" is used,
+	// unless NoPrefix is set.
+	Prefix string
+
+	// NoPrefix omits the prefix altogether.
+	NoPrefix bool
+}
+
+// prefix returns the prefix selected by opts.
+func (opts FormatOptions) prefix() string {
+	if opts.NoPrefix {
+		return ""
+	}
+	if opts.Prefix == "" {
+		return scheme
+	}
+	return opts.Prefix
+}
+
+// combined reports whether opts encodes the digest and salt as a whole.
+func (opts FormatOptions) combined() bool {
+	return opts.Delimiter == "" && opts.HashEncoding == opts.SaltEncoding
+}
+
+// encode returns b encoded with e. An unknown encoding is treated as
+// EncodingBase64.
+func (e Encoding) encode(b []byte) string {
+	switch e {
+	case EncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(b)
+	case EncodingHex:
+		return hex.EncodeToString(b)
+	default:
+		return base64.StdEncoding.EncodeToString(b)
+	}
+}
+
+// decode decodes s encoded with e.
+func (e Encoding) decode(s string) ([]byte, error) {
+	switch e {
+	case EncodingBase64URL:
+		b, err := base64.URLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+		}
+		return b, nil
+	case EncodingHex:
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidHex, err)
+		}
+		return b, nil
+	default:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+		}
+		return b, nil
+	}
+}
+
+// encodedLen returns the length of n bytes encoded with e.
+func (e Encoding) encodedLen(n int) int {
+	if e == EncodingHex {
+		return hex.EncodedLen(n)
+	}
+	return base64.StdEncoding.EncodedLen(n)
+}
+
+// Format returns the sum laid out as specified by opts, e.g. with a hex
+// encoded salt following a base-64 encoded digest, for tools expecting
+// such non-standard forms. Unless the digest and salt are encoded as a
+// whole, the digest always comes first, whatever the salt position.
+// ParseFormat reverses Format.
+func (d *digest) Format(opts FormatOptions) string {
+	sum := d.Sum(nil)
+	if opts.combined() {
+		return opts.prefix() + opts.HashEncoding.encode(sum)
+	}
+
+	sha1Part, salt := sum[:sha1.Size], sum[sha1.Size:]
+	if d.pos == SaltPrefix {
+		salt, sha1Part = sum[:len(d.salt)], sum[len(d.salt):]
+	}
+	return opts.prefix() + opts.HashEncoding.encode(sha1Part) + opts.Delimiter +
+		opts.SaltEncoding.encode(salt)
+}
+
+// ParseFormat decodes a hash produced by Format with the same options,
+// returning the SHA-1 digest followed by the salt, as accepted by
+// Validate; a hash encoded as a whole is returned as laid out by Sum. The
+// prefix selected by opts must be present, otherwise
+// ErrMalformedPrefix is returned.
+func ParseFormat(formatted string, opts FormatOptions) ([]byte, error) {
+	prefix := opts.prefix()
+	if !strings.HasPrefix(formatted, prefix) {
+		return nil, ErrMalformedPrefix
+	}
+	payload := formatted[len(prefix):]
+
+	if opts.combined() {
+		return opts.HashEncoding.decode(payload)
+	}
+
+	// the encoded digest has a fixed length, so the delimiter need not be
+	// searched for and may even occur within the encoded salt
+	n := opts.HashEncoding.encodedLen(sha1.Size)
+	if len(payload) < n || !strings.HasPrefix(payload[n:], opts.Delimiter) {
+		return nil, ErrMalformedFormat
+	}
+
+	sha1Part, err := opts.HashEncoding.decode(payload[:n])
+	if err != nil {
+		return nil, err
+	}
+	salt, err := opts.SaltEncoding.decode(payload[n+len(opts.Delimiter):])
+	if err != nil {
+		return nil, err
+	}
+	return append(sha1Part, salt...), nil
+}
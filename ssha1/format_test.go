@@ -0,0 +1,105 @@
+package ssha1
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type formatCase struct {
+	opts     FormatOptions
+	expected string
+}
+
+func TestFormat(t *testing.T) {
+	salt := []byte("f0rM4tS4")
+	plaintext := []byte("Simplicity is the ultimate sophistication.")
+
+	cases := []formatCase{
+		{FormatOptions{}, "{SSHA}JBhqaACoHFO/er1m+voRu/L0yRJmMHJNNHRTNA=="},
+		{FormatOptions{SaltEncoding: EncodingHex, Delimiter: "$", Prefix: "{X-SSHA}"},
+			"{X-SSHA}JBhqaACoHFO/er1m+voRu/L0yRI=$6630724d34745334"},
+		{FormatOptions{HashEncoding: EncodingHex, Delimiter: ":", NoPrefix: true},
+			"24186a6800a81c53bf7abd66fafa11bbf2f4c912:ZjByTTR0UzQ="},
+		{FormatOptions{SaltEncoding: EncodingBase64URL},
+			"{SSHA}JBhqaACoHFO/er1m+voRu/L0yRI=ZjByTTR0UzQ="},
+		{FormatOptions{HashEncoding: EncodingHex, SaltEncoding: EncodingHex, NoPrefix: true},
+			"24186a6800a81c53bf7abd66fafa11bbf2f4c9126630724d34745334"},
+	}
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write(plaintext)
+	d := c.(*digest)
+
+	if result := d.Format(FormatOptions{}); result != c.String() {
+		t.Errorf("Format() with the zero options = %s; expected String() %s", result, c.String())
+	}
+
+	for _, tc := range cases {
+		result := d.Format(tc.opts)
+		if result != tc.expected {
+			t.Errorf("Format(%+v) = %s; expected %s", tc.opts, result, tc.expected)
+		}
+
+		ssha1Hash, err := ParseFormat(result, tc.opts)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, tc)
+			continue
+		}
+		if !bytes.Equal(ssha1Hash, c.Sum(nil)) {
+			t.Errorf("ParseFormat(%s) = %x; expected %x", result, ssha1Hash, c.Sum(nil))
+		}
+		if valid, err := Validate(ssha1Hash, plaintext); err != nil || !valid {
+			t.Errorf("Validate() of the parsed hash = %t, %v; expected true, nil", valid, err)
+		}
+	}
+}
+
+func TestFormatSaltPrefix(t *testing.T) {
+	c, err := NewWithSaltPosition([]byte("f0rM4tS4"), SaltPrefix)
+	if err != nil {
+		t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("Simplicity is the ultimate sophistication."))
+
+	opts := FormatOptions{SaltEncoding: EncodingHex, Delimiter: "$"}
+	ssha1Hash, err := ParseFormat(c.(*digest).Format(opts), opts)
+	if err != nil {
+		t.Errorf("method ParseFormat() returned unexpected error: %e", err)
+	}
+
+	// the digest comes first whatever the salt position
+	sum := c.Sum(nil)
+	expected := append(append([]byte(nil), sum[len("f0rM4tS4"):]...), sum[:len("f0rM4tS4")]...)
+	if !bytes.Equal(ssha1Hash, expected) {
+		t.Errorf("ParseFormat() = %x; expected %x", ssha1Hash, expected)
+	}
+}
+
+type parseFormatErrorCase struct {
+	formatted string
+	opts      FormatOptions
+	expected  error
+}
+
+func TestParseFormatErrors(t *testing.T) {
+	hexOpts := FormatOptions{SaltEncoding: EncodingHex, Delimiter: "$"}
+
+	cases := []parseFormatErrorCase{
+		{"JBhqaACoHFO/er1m+voRu/L0yRJmMHJNNHRTNA==", FormatOptions{}, ErrMalformedPrefix},
+		{"{SSHA}JBhqaACoHFO/er1m+voRu/L0yRJmMHJNNHRTNA=!", FormatOptions{}, ErrInvalidBase64},
+		{"{SSHA}JBhqaACoHFO/er1m+voRu", hexOpts, ErrMalformedFormat},
+		{"{SSHA}JBhqaACoHFO/er1m+voRu/L0yRI=:6630724d34745334", hexOpts, ErrMalformedFormat},
+		{"{SSHA}JBhqaACoHFO/er1m+voRu/L0yRI=$66307g4d34745334", hexOpts, ErrInvalidHex},
+		{"{SSHA}JBhqaACoHFO/er1m+voRu/L0y!!=$6630724d34745334", hexOpts, ErrInvalidBase64},
+	}
+
+	for _, tc := range cases {
+		if _, err := ParseFormat(tc.formatted, tc.opts); !errors.Is(err, tc.expected) {
+			t.Errorf("ParseFormat(%q) error = %v; expected %v", tc.formatted, err, tc.expected)
+		}
+	}
+}
@@ -2,4 +2,7 @@ module github.com/kristinjeanna/crypto/ssha1
 
 go 1.18
 
-require github.com/kristinjeanna/crypto v1.0.0 // indirect
+require (
+	github.com/kristinjeanna/crypto v1.1.0
+	golang.org/x/text v0.21.0
+)
@@ -0,0 +1,46 @@
+package ssha1
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrMalformedHtpasswd is returned when an htpasswd line or user name
+// cannot be parsed or formatted.
+var ErrMalformedHtpasswd = errors.New("malformed htpasswd entry, expected user:{SSHA}base64")
+
+// FormatHtpasswd returns an Apache htpasswd line of the form
+// "user:{SSHA}base64" for the specified user and password. A random salt
+// of the default size is generated via the crypto/rand package. The user
+// name must be non-empty and must not contain a colon or a line break.
+// Apache's own htpasswd tool cannot write "{SSHA}" entries, only unsalted
+// "{SHA}" ones, so such lines are meant for servers that read "{SSHA}",
+// e.g. nginx.
+func FormatHtpasswd(user string, password []byte) (line string, err error) {
+	if user == "" || strings.ContainsAny(user, ":\r\n") {
+		return "", ErrMalformedHtpasswd
+	}
+
+	d, err := New()
+	if err != nil {
+		return "", err
+	}
+	d.Write(password)
+	return user + ":" + d.String(), nil
+}
+
+// VerifyHtpasswd returns true if password matches the "{SSHA}" hash in the
+// specified Apache htpasswd line; false, otherwise. Trailing line breaks
+// are ignored. Entries using any other scheme are rejected with
+// ErrMalformedPrefix.
+func VerifyHtpasswd(line string, password []byte) (bool, error) {
+	line = strings.TrimRight(line, "\r\n")
+	user, stored, found := strings.Cut(line, ":")
+	if !found || user == "" || stored == "" {
+		return false, ErrMalformedHtpasswd
+	}
+	if !strings.HasPrefix(stored, scheme) {
+		return false, ErrMalformedPrefix
+	}
+	return ValidateString(stored, password)
+}
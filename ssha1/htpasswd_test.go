@@ -0,0 +1,74 @@
+package ssha1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormatHtpasswd(t *testing.T) {
+	line, err := FormatHtpasswd("alice", []byte("s3cr3t"))
+	if err != nil {
+		t.Errorf("method FormatHtpasswd() returned unexpected error: %e", err)
+	}
+	if !strings.HasPrefix(line, "alice:{SSHA}") {
+		t.Errorf("FormatHtpasswd result = %s; expected prefix %s", line, "alice:{SSHA}")
+	}
+
+	if result, err := VerifyHtpasswd(line, []byte("s3cr3t")); err != nil || !result {
+		t.Errorf("VerifyHtpasswd() = %t, %v; expected true, nil", result, err)
+	}
+	if result, err := VerifyHtpasswd(line, []byte("S3cr3t")); err != nil || result {
+		t.Errorf("VerifyHtpasswd() = %t, %v; expected false, nil", result, err)
+	}
+
+	for _, user := range []string{"", "bob:smith", "carol\n"} {
+		if _, err := FormatHtpasswd(user, []byte("s3cr3t")); !errors.Is(err, ErrMalformedHtpasswd) {
+			t.Errorf("FormatHtpasswd(%q) error = %v; expected %v", user, err, ErrMalformedHtpasswd)
+		}
+	}
+}
+
+type htpasswdCase struct {
+	line        string
+	password    []byte
+	expected    bool
+	expectedErr error
+}
+
+func TestVerifyHtpasswd(t *testing.T) {
+	// Apache's htpasswd tool cannot emit {SSHA} entries, so no tool output
+	// is available; the {SSHA} lines below reuse the vectors of TestString
+	// and TestValidateString, i.e. this package's own output, under made-up
+	// user names. Only the {SHA} line is genuine htpasswd -s output.
+	cases := []htpasswdCase{
+		// salt: "R*w.5Vmo"
+		{"dave:{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), true, nil},
+		{"dave:{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==\n", []byte("You have to be odd to be number one."), true, nil},
+		{"dave:{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number two."), false, nil},
+		// salt: "abcdefg"
+		{"erin:{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", []byte("1234567890"), true, nil},
+		// missing colon
+		{"dave{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil, false, ErrMalformedHtpasswd},
+		// missing user
+		{":{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil, false, ErrMalformedHtpasswd},
+		// unsalted SHA-1 of "password", as written by htpasswd -s
+		{"frank:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=", []byte("password"), false, ErrMalformedPrefix},
+		// bcrypt
+		{"grace:$2y$05$c4WoMPo3SXsafkva.HHa6uXQZWr7oboPiC2bT/r7q1BB8I2s0BRqC", nil, false, ErrMalformedPrefix},
+	}
+
+	for _, c := range cases {
+		result, err := VerifyHtpasswd(c.line, c.password)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("VerifyHtpasswd(%q) error = %v; expected %v", c.line, err, c.expectedErr)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("verification test failed for test case %v", c)
+		}
+	}
+}
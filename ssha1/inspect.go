@@ -0,0 +1,63 @@
+package ssha1
+
+// minDistinctSaltBytes is the number of distinct byte values below which
+// InspectSalt reports a salt as low-entropy.
+const minDistinctSaltBytes int = 4
+
+// SaltReport describes the salt of a stored SSHA1 hash, as returned by
+// InspectSalt.
+type SaltReport struct {
+	// Salt is a copy of the salt bytes. Comparing the salts of several
+	// stored hashes reveals salt reuse.
+	Salt []byte
+
+	// Size is the number of salt bytes.
+	Size int
+
+	// DistinctBytes is the number of distinct byte values in the salt.
+	DistinctBytes int
+
+	// AllZero is true if every byte of the salt is zero.
+	AllZero bool
+
+	// LowEntropy is true if the salt has fewer than 4 distinct byte
+	// values, which includes all salts shorter than 4 bytes.
+	LowEntropy bool
+}
+
+// InspectSalt decodes the specified base-64 encoded SSHA1 hash, which is
+// accepted in any form supported by ValidateString, and reports on the
+// strength of its salt. This is meant for auditing stored credentials for
+// weak salts; it does not validate anything.
+func InspectSalt(encoded string) (SaltReport, error) {
+	ssha1Hash, err := parseString(encoded)
+	if err != nil {
+		return SaltReport{}, err
+	}
+
+	_, salt, err := Decode(ssha1Hash)
+	if err != nil {
+		return SaltReport{}, err
+	}
+
+	var seen [256]bool
+	distinct := 0
+	allZero := true
+	for _, b := range salt {
+		if !seen[b] {
+			seen[b] = true
+			distinct++
+		}
+		if b != 0 {
+			allZero = false
+		}
+	}
+
+	return SaltReport{
+		Salt:          append([]byte(nil), salt...),
+		Size:          len(salt),
+		DistinctBytes: distinct,
+		AllZero:       allZero,
+		LowEntropy:    distinct < minDistinctSaltBytes,
+	}, nil
+}
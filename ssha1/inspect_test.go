@@ -0,0 +1,65 @@
+package ssha1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+type inspectSaltCase struct {
+	encoded  string
+	expected SaltReport
+}
+
+func TestInspectSalt(t *testing.T) {
+	random, err := hex.DecodeString("9f3ac1e07b52d84e16a0f7c3")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+
+	cases := []inspectSaltCase{
+		// salt: 8 zero bytes
+		{"{SSHA}XMr4KP6J7hAGe2m0VcXuIHdb9MEAAAAAAAAAAA==", SaltReport{make([]byte, 8), 8, 1, true, true}},
+		// salt: "aaaabbbb"
+		{"{SSHA}EaleHgocg57YLZaKciBa1wLBlJlhYWFhYmJiYg==", SaltReport{[]byte("aaaabbbb"), 8, 2, false, true}},
+		// salt: "X"
+		{"{SSHA}aRvqrBMKC+JdxRfeTmORM009DzdY", SaltReport{[]byte("X"), 1, 1, false, true}},
+		// salt: 12 random bytes
+		{"{SSHA}3HXzLhyyHkGM41DO/m7I5cXXLEyfOsHge1LYThag98M=", SaltReport{random, 12, 12, false, false}},
+		// salt: "R*w.5Vmo", no prefix
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", SaltReport{[]byte("R*w.5Vmo"), 8, 8, false, false}},
+	}
+
+	for _, c := range cases {
+		result, err := InspectSalt(c.encoded)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+		if !bytes.Equal(result.Salt, c.expected.Salt) {
+			t.Errorf("InspectSalt(%q) Salt = %x; expected %x", c.encoded, result.Salt, c.expected.Salt)
+		}
+		if result.Size != c.expected.Size || result.DistinctBytes != c.expected.DistinctBytes ||
+			result.AllZero != c.expected.AllZero || result.LowEntropy != c.expected.LowEntropy {
+			t.Errorf("InspectSalt(%q) = %+v; expected %+v", c.encoded, result, c.expected)
+		}
+	}
+}
+
+func TestInspectSaltErrors(t *testing.T) {
+	cases := []struct {
+		encoded     string
+		expectedErr error
+	}{
+		{"{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", ErrMalformedPrefix},
+		{"{SSHA}not*valid*base64!", ErrInvalidBase64},
+		{"{SSHA}mrUPJ9QgHbmyhIO6g8SOuvuyqhc=", ErrSliceTooShortSSHA1},
+	}
+
+	for _, c := range cases {
+		if _, err := InspectSalt(c.encoded); !errors.Is(err, c.expectedErr) {
+			t.Errorf("InspectSalt(%q) error = %v; expected %v", c.encoded, err, c.expectedErr)
+		}
+	}
+}
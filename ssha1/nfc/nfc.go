@@ -0,0 +1,31 @@
+// Package nfc validates SSHA1 hashes against passwords converted to
+// Unicode Normalization Form C (NFC). It is kept apart from the ssha1
+// package, which compares the exact bytes of a password, so that only
+// its importers depend on golang.org/x/text.
+package nfc
+
+import (
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/kristinjeanna/crypto/ssha1"
+)
+
+// ValidateString is like ssha1.ValidateString, but converts the sample to
+// NFC before hashing it. A password can be typed as different byte
+// sequences that render identically, e.g. "é" as a single code point or as
+// "e" followed by a combining accent; with normalization, these validate
+// the same. The stored hash must have been computed from NFC-normalized
+// input for this to match, e.g. by writing norm.NFC.Bytes(password) to the
+// hash.
+//
+// ssha1.ValidateString compares the exact bytes of the sample and remains
+// the default.
+func ValidateString(encoded string, sample []byte) (bool, error) {
+	return ssha1.ValidateString(encoded, norm.NFC.Bytes(sample))
+}
+
+// Verify is like ssha1.Verify, but converts the password to NFC before
+// hashing it, as described for ValidateString.
+func Verify(stored string, password []byte) (bool, error) {
+	return ValidateString(stored, password)
+}
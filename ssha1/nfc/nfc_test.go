@@ -0,0 +1,50 @@
+package nfc
+
+import (
+	"testing"
+
+	"github.com/kristinjeanna/crypto/ssha1"
+)
+
+const (
+	composed   = "caf\u00e9 cr\u00e8me"   // "é" and "è" as single code points (NFC)
+	decomposed = "cafe\u0301 cre\u0300me" // base letters followed by combining accents (NFD)
+)
+
+type normalizeCase struct {
+	password    string
+	expected    bool
+	expectedNFC bool
+}
+
+func TestValidateString(t *testing.T) {
+	c, err := ssha1.NewWithSalt([]byte("n0rmS4lt"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte(composed))
+	stored := c.String()
+
+	cases := []normalizeCase{
+		// byte-exact validation only accepts the composed form
+		{composed, true, true},
+		{decomposed, false, true},
+		{"cafe creme", false, false},
+	}
+
+	for _, tc := range cases {
+		if result, err := ssha1.Verify(stored, []byte(tc.password)); err != nil || result != tc.expected {
+			t.Errorf("ssha1.Verify(%q) = %t, %v; expected %t, nil", tc.password, result, err, tc.expected)
+		}
+		if result, err := Verify(stored, []byte(tc.password)); err != nil || result != tc.expectedNFC {
+			t.Errorf("Verify(%q) = %t, %v; expected %t, nil", tc.password, result, err, tc.expectedNFC)
+		}
+		if result, err := ValidateString(stored, []byte(tc.password)); err != nil || result != tc.expectedNFC {
+			t.Errorf("ValidateString(%q) = %t, %v; expected %t, nil", tc.password, result, err, tc.expectedNFC)
+		}
+	}
+
+	if _, err := ValidateString("{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte(composed)); err == nil {
+		t.Errorf("ValidateString() accepted a malformed prefix")
+	}
+}
@@ -0,0 +1,86 @@
+package ssha1
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+// openLDAPCases are "{SSHA}" values in the layout written by OpenLDAP's
+// slappasswd, base64(SHA1(password || salt) || salt) with a 4-byte salt.
+// No slappasswd output is available to these tests: the values were not
+// produced by this package, but their origin could not be confirmed
+// either, so they were only cross-checked against SHA-1 from Python's
+// hashlib. The passlib fixtures in passlib_test.go are the verified
+// third-party vectors.
+var openLDAPCases = []struct {
+	stored   string
+	password string
+}{
+	{"{SSHA}DkMTwBl+a/3DQTxCYEApdUtNXGgdUac3", "secret"},
+	{"{SSHA}pKqkNr1tq3wtQqk+UcPyA3HnA2NsU5NJ", "password"},
+}
+
+func TestOpenLDAPCompatibility(t *testing.T) {
+	for _, c := range openLDAPCases {
+		if result, err := ValidateString(c.stored, []byte(c.password)); err != nil || !result {
+			t.Errorf("ValidateString(%q, %q) = %t, %v; expected true, nil", c.stored, c.password, result, err)
+		}
+		if result, err := crypto.ValidateAny(c.stored, []byte(c.password)); err != nil || !result {
+			t.Errorf("ValidateAny(%q, %q) = %t, %v; expected true, nil", c.stored, c.password, result, err)
+		}
+
+		for _, wrong := range []string{"", c.password + "x", c.password[1:], "Secret"} {
+			if result, err := ValidateString(c.stored, []byte(wrong)); err != nil || result {
+				t.Errorf("ValidateString(%q, %q) = %t, %v; expected false, nil", c.stored, wrong, result, err)
+			}
+		}
+	}
+}
+
+func TestOpenLDAPRoundTrip(t *testing.T) {
+	// re-hashing with the salt of an OpenLDAP value yields that value
+	for _, c := range openLDAPCases {
+		ssha1Hash, err := parseString(c.stored)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+		_, salt, err := Decode(ssha1Hash)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+
+		h, err := NewWithSalt(salt)
+		if err != nil {
+			t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+			continue
+		}
+		h.Write([]byte(c.password))
+		if result := h.String(); result != c.stored {
+			t.Errorf("String result = %s; expected %s", result, c.stored)
+		}
+	}
+}
+
+func TestOpenLDAPExportForms(t *testing.T) {
+	// the value of an LDIF "userPassword::" line for the first case
+	ldif := "e1NTSEF9RGtNVHdCbCthLzNEUVR4Q1lFQXBkVXROWEdnZFVhYzM="
+	decoded, err := base64.StdEncoding.DecodeString(ldif)
+	if err != nil {
+		t.Errorf("unexpected error decoding base64 string: %e", err)
+	}
+	if result, err := ValidateString(string(decoded), []byte("secret")); err != nil || !result {
+		t.Errorf("ValidateString(%q) = %t, %v; expected true, nil", decoded, result, err)
+	}
+
+	lower := "{ssha}DkMTwBl+a/3DQTxCYEApdUtNXGgdUac3"
+	if result, err := ValidateString(lower, []byte("secret")); err != nil || !result {
+		t.Errorf("ValidateString(%q) = %t, %v; expected true, nil", lower, result, err)
+	}
+	if result, err := crypto.VerifyAny(lower, []byte("secret")); err != nil || !result {
+		t.Errorf("VerifyAny(%q) = %t, %v; expected true, nil", lower, result, err)
+	}
+}
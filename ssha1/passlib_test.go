@@ -0,0 +1,65 @@
+package ssha1
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// passlibCases are the known-correct hashes from the test suite of Python's
+// passlib for its ldap_salted_sha1 handler, which stores
+// "{SSHA}" + base64(SHA1(password || salt) || salt) with a 4 to 16 byte
+// salt.
+var passlibCases = []struct {
+	stored   string
+	password string
+}{
+	{"{SSHA}0c0blFTXXNuAMHECS4uxrj3ZieMoWImr", "testing123"},
+	{"{SSHA}0H+zTv8o4MR4H43n03eCsvw1luG8LdB7", "secret"},
+	{"{SSHA}3yCSD1nLZXznra4N8XzZgAL+s1sQYsx5", "táБℓə"},
+	// alternate salt sizes: 8, 15 and 16 bytes
+	{"{SSHA}P90+qijSp8MJ1tN25j5o1PflUvlqjXHOGeOckw==", "test"},
+	{"{SSHA}/ZMF5KymNM+uEOjW+9STKlfCFj51bg3BmBNCiPHeW2ttbU0=", "test"},
+	{"{SSHA}Pfx6Vf48AT9x3FVv8znbo8WQkEVSipHSWovxXmvNWUvp/d/7", "test"},
+}
+
+func TestPasslibCompatibility(t *testing.T) {
+	for _, c := range passlibCases {
+		if result, err := ValidateString(c.stored, []byte(c.password)); err != nil || !result {
+			t.Errorf("ValidateString(%q, %q) = %t, %v; expected true, nil", c.stored, c.password, result, err)
+		}
+		if result, err := ValidateString(c.stored, []byte(c.password+"x")); err != nil || result {
+			t.Errorf("ValidateString(%q, %q) = %t, %v; expected false, nil", c.stored, c.password+"x", result, err)
+		}
+
+		ssha1Hash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(c.stored, scheme))
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+		if result, err := Validate(ssha1Hash, []byte(c.password)); err != nil || !result {
+			t.Errorf("Validate(%x, %q) = %t, %v; expected true, nil", ssha1Hash, c.password, result, err)
+		}
+	}
+}
+
+func TestPasslibSaltSizes(t *testing.T) {
+	// hashes created with a passlib-compatible salt size have the layout of
+	// the passlib fixtures: a 20-byte digest followed by the salt
+	for _, size := range []int{4, 8, 16} {
+		h, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		h.Write([]byte("test"))
+
+		stored := h.String()
+		if n, err := SaltSizeOf(stored); err != nil || n != size {
+			t.Errorf("SaltSizeOf(%q) = %d, %v; expected %d", stored, n, err, size)
+		}
+		if result, err := ValidateString(stored, []byte("test")); err != nil || !result {
+			t.Errorf("ValidateString(%q) = %t, %v; expected true, nil", stored, result, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ssha1
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+// Errors returned for peppered hashes.
+var (
+	// ErrEmptyPepper is returned when NewWithSaltAndPepper or
+	// ValidateWithPepper is passed an empty pepper.
+	ErrEmptyPepper = errors.New("pepper is empty")
+
+	// ErrPepperedState is returned by MarshalBinary for a digest created
+	// with a pepper.
+	ErrPepperedState = errors.New("cannot marshal the state of a peppered hash")
+)
+
+// NewWithSaltAndPepper returns a new hash.Hash with the specified salt and
+// pepper, an application-wide secret that is mixed in along with the salt
+// but, unlike the salt, is not part of the output: Sum returns
+// SHA1(data || pepper || salt) || salt. Salt size must be between 1 and
+// 1024 bytes and the pepper must not be empty; the pepper is copied.
+//
+// A pepper keeps stolen hashes from being attacked offline unless the
+// pepper is stolen too, so it must be kept apart from the hashes, e.g. in
+// a secrets manager rather than the database. In exchange, losing or
+// changing the pepper invalidates every hash created with it, and the
+// hashes are no longer "{SSHA}" values that other tools can validate. A
+// pepper does nothing to make SHA-1 slower to brute-force.
+func NewWithSaltAndPepper(salt, pepper []byte) (crypto.Hash, error) {
+	if len(pepper) == 0 {
+		return nil, ErrEmptyPepper
+	}
+	h, err := NewWithSalt(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	d := h.(*digest)
+	d.pepper = append([]byte(nil), pepper...)
+	return d, nil
+}
+
+// ValidateWithPepper returns true if the SSHA1 hash of the sample, created
+// by NewWithSaltAndPepper with the specified pepper, matches the specified
+// SSHA1 hash; false, otherwise. The hashes are compared in constant time.
+func ValidateWithPepper(ssha1Hash, pepper, sample []byte) (bool, error) {
+	if len(pepper) == 0 {
+		return false, ErrEmptyPepper
+	}
+	_, salt, err := split(ssha1Hash, SaltSuffix)
+	if err != nil {
+		return false, err
+	}
+
+	d, err := NewWithSaltAndPepper(salt, pepper)
+	if err != nil {
+		return false, err
+	}
+
+	d.Write(sample)
+	return subtle.ConstantTimeCompare(ssha1Hash, d.Sum(nil)) == 1, nil
+}
@@ -0,0 +1,109 @@
+package ssha1
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+func TestNewWithSaltAndPepper(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	salt := []byte("n4pggXWL")
+
+	sums := make(map[string]string)
+	for _, pepper := range []string{"pepper one", "pepper two"} {
+		h, err := NewWithSaltAndPepper(salt, []byte(pepper))
+		if err != nil {
+			t.Errorf("method NewWithSaltAndPepper() returned unexpected error: %e", err)
+			continue
+		}
+		h.Write(password)
+		sum := h.Sum(nil)
+
+		// SHA1(password || pepper || salt) || salt
+		expected, err := Sum(append(append([]byte(nil), password...), pepper...), salt)
+		if err != nil {
+			t.Errorf("method Sum() returned unexpected error: %e", err)
+		}
+		if !bytes.Equal(sum, expected) {
+			t.Errorf("Sum result = %x; expected %x", sum, expected)
+		}
+		if !bytes.HasSuffix(sum, salt) || len(sum) != SumSize(len(salt)) {
+			t.Errorf("Sum result = %x; expected the salt %q, and no pepper, to follow the digest", sum, salt)
+		}
+		sums[string(sum)] = pepper
+	}
+	if len(sums) != 2 {
+		t.Errorf("different peppers yielded the same hash")
+	}
+
+	unpeppered, err := Sum(password, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if _, found := sums[string(unpeppered)]; found {
+		t.Errorf("peppered hash equals the unpeppered one")
+	}
+}
+
+func TestValidateWithPepper(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	pepper := []byte("application secret")
+
+	h, err := NewWithSaltAndPepper([]byte("n4pggXWL"), pepper)
+	if err != nil {
+		t.Errorf("method NewWithSaltAndPepper() returned unexpected error: %e", err)
+	}
+	h.Write(password)
+	stored := h.Sum(nil)
+
+	if result, err := ValidateWithPepper(stored, pepper, password); err != nil || !result {
+		t.Errorf("ValidateWithPepper() = %t, %v; expected true, nil", result, err)
+	}
+	if result, err := ValidateWithPepper(stored, []byte("other secret"), password); err != nil || result {
+		t.Errorf("ValidateWithPepper() with wrong pepper = %t, %v; expected false, nil", result, err)
+	}
+	if result, err := ValidateWithPepper(stored, pepper, password[1:]); err != nil || result {
+		t.Errorf("ValidateWithPepper() with wrong password = %t, %v; expected false, nil", result, err)
+	}
+	if result, err := Validate(stored, password); err != nil || result {
+		t.Errorf("Validate() without pepper = %t, %v; expected false, nil", result, err)
+	}
+}
+
+func TestPepperErrors(t *testing.T) {
+	if _, err := NewWithSaltAndPepper([]byte("n4pggXWL"), nil); !errors.Is(err, ErrEmptyPepper) {
+		t.Errorf("NewWithSaltAndPepper() error = %v; expected %v", err, ErrEmptyPepper)
+	}
+	if _, err := NewWithSaltAndPepper(nil, []byte("pepper")); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("NewWithSaltAndPepper() error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := ValidateWithPepper(make([]byte, 28), []byte{}, nil); !errors.Is(err, ErrEmptyPepper) {
+		t.Errorf("ValidateWithPepper() error = %v; expected %v", err, ErrEmptyPepper)
+	}
+	if _, err := ValidateWithPepper(make([]byte, 20), []byte("pepper"), nil); !errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("ValidateWithPepper() error = %v; expected %v", err, ErrSliceTooShortSSHA1)
+	}
+
+	h, err := NewWithSaltAndPepper([]byte("n4pggXWL"), []byte("pepper"))
+	if err != nil {
+		t.Errorf("method NewWithSaltAndPepper() returned unexpected error: %e", err)
+	}
+	if _, err := h.(encoding.BinaryMarshaler).MarshalBinary(); !errors.Is(err, ErrPepperedState) {
+		t.Errorf("MarshalBinary() error = %v; expected %v", err, ErrPepperedState)
+	}
+}
+
+func TestPepperClone(t *testing.T) {
+	h, err := NewWithSaltAndPepper([]byte("n4pggXWL"), []byte("pepper"))
+	if err != nil {
+		t.Errorf("method NewWithSaltAndPepper() returned unexpected error: %e", err)
+	}
+	h.Write([]byte("data"))
+	if result, expected := h.(crypto.Cloner).Clone().Sum(nil), h.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Clone Sum result = %x; expected %x", result, expected)
+	}
+}
@@ -0,0 +1,58 @@
+package ssha1
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// phcID is the algorithm identifier used in PHC strings.
+const phcID string = "ssha1"
+
+// ErrMalformedPHC is returned when a PHC string is not of the form
+// "$ssha1$<salt>$<hash>".
+var ErrMalformedPHC = errors.New("malformed PHC string, expected $" + phcID + "$<salt>$<hash>")
+
+// PHCString returns the sum in the PHC string format,
+// "$ssha1$" || base64(salt) || "$" || base64(sha1), using the unpadded
+// base-64 encoding required by the format. This allows SSHA1 hashes to be
+// kept alongside those of other algorithms in a store of PHC strings. As
+// with CryptString, ValidatePHC takes the salt to follow the data when
+// hashing.
+func (d *digest) PHCString() string {
+	sum := d.Sum(nil)
+	sha1Part, salt := sum[:sha1.Size], sum[sha1.Size:]
+	if d.pos == SaltPrefix {
+		salt, sha1Part = sum[:len(d.salt)], sum[len(d.salt):]
+	}
+
+	return "$" + phcID + "$" + base64.RawStdEncoding.EncodeToString(salt) +
+		"$" + base64.RawStdEncoding.EncodeToString(sha1Part)
+}
+
+// ValidatePHC returns true if the SSHA1 hash of the sample matches the
+// specified hash in the PHC string format produced by PHCString; false,
+// otherwise. The base-64 fields must be unpadded. The hashes are compared
+// in constant time.
+func ValidatePHC(phc string, sample []byte) (bool, error) {
+	fields := strings.Split(phc, "$")
+	if len(fields) != 4 || fields[0] != "" || fields[1] != phcID {
+		return false, ErrMalformedPHC
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(fields[2])
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+	}
+	sha1Part, err := base64.RawStdEncoding.DecodeString(fields[3])
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidBase64, err)
+	}
+	if len(sha1Part) != sha1.Size {
+		return false, ErrMalformedPHC
+	}
+
+	return Validate(append(sha1Part, salt...), sample)
+}
@@ -0,0 +1,104 @@
+package ssha1
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPHCString(t *testing.T) {
+	c, err := NewWithSalt([]byte("abcdefg"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("1234567890"))
+
+	expected := "$ssha1$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6MbLV4"
+	phc := c.(*digest).PHCString()
+	if phc != expected {
+		t.Errorf("PHCString() = %s; expected %s", phc, expected)
+	}
+	if strings.Contains(phc, "=") {
+		t.Errorf("PHCString() = %s; expected no padding", phc)
+	}
+}
+
+func TestPHCRoundTrip(t *testing.T) {
+	plaintext := []byte("It does not matter how slowly you go as long as you do not stop.")
+	for _, size := range []int{1, 2, 3, 4, 8, 20, MaxSaltBytes} {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		c.Write(plaintext)
+		phc := c.(*digest).PHCString()
+
+		if result, err := ValidatePHC(phc, plaintext); err != nil || !result {
+			t.Errorf("ValidatePHC(%q) = %t, %v; expected true, nil", phc, result, err)
+		}
+		if result, err := ValidatePHC(phc, plaintext[1:]); err != nil || result {
+			t.Errorf("ValidatePHC(%q) = %t, %v; expected false, nil", phc, result, err)
+		}
+
+		// the PHC and raw forms of a credential agree
+		for _, sample := range [][]byte{plaintext, plaintext[1:]} {
+			phcResult, _ := ValidatePHC(phc, sample)
+			rawResult, err := Validate(c.Sum(nil), sample)
+			if err != nil {
+				t.Errorf("method Validate() returned unexpected error: %e", err)
+			}
+			if phcResult != rawResult {
+				t.Errorf("ValidatePHC() = %t; Validate() = %t", phcResult, rawResult)
+			}
+		}
+	}
+}
+
+func TestValidatePHCAgreesWithValidate(t *testing.T) {
+	// salt: "abcdefg"
+	ssha1Hash, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+	phc := "$ssha1$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6MbLV4"
+
+	for _, sample := range []string{"1234567890", "123456789", ""} {
+		expected, err := Validate(ssha1Hash, []byte(sample))
+		if err != nil {
+			t.Errorf("method Validate() returned unexpected error: %e", err)
+		}
+		result, err := ValidatePHC(phc, []byte(sample))
+		if err != nil {
+			t.Errorf("method ValidatePHC() returned unexpected error: %e", err)
+		}
+		if result != expected {
+			t.Errorf("ValidatePHC(%q) = %t; Validate returned %t", sample, result, expected)
+		}
+	}
+}
+
+type validatePHCErrorCase struct {
+	phc      string
+	expected error
+}
+
+func TestValidatePHCErrors(t *testing.T) {
+	cases := []validatePHCErrorCase{
+		{"", ErrMalformedPHC},
+		{"ssha1$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6MbLV4", ErrMalformedPHC},
+		{"$ssha$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6MbLV4", ErrMalformedPHC},
+		{"$ssha1$v=1$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6MbLV4", ErrMalformedPHC},
+		{"$ssha1$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6Mb", ErrMalformedPHC},
+		{"$ssha1$YWJjZGVmZw==$hBdoDAlkTfdD186hNm++E6MbLV4", ErrInvalidBase64},
+		{"$ssha1$YWJjZGVmZw$hBdoDAlkTfdD186hNm++E6MbLV4=", ErrInvalidBase64},
+		{"$ssha1$$hBdoDAlkTfdD186hNm++E6MbLV4", ErrSliceTooShortSSHA1},
+	}
+
+	for _, c := range cases {
+		if _, err := ValidatePHC(c.phc, nil); !errors.Is(err, c.expected) {
+			t.Errorf("ValidatePHC(%q) error = %v; expected %v", c.phc, err, c.expected)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ssha1
+
+import (
+	"sync"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+var digestPool = sync.Pool{
+	New: func() interface{} { return new(digest) },
+}
+
+// AcquireHash returns a hash.Hash with the specified salt, as NewWithSalt
+// does, but reuses a hash previously returned to the pool with ReleaseHash
+// if one is available. This reduces allocations in workloads that create
+// and discard many hashes. It is safe to call from multiple goroutines.
+func AcquireHash(salt []byte) (crypto.Hash, error) {
+	if salt == nil {
+		return nil, ErrNilSalt
+	}
+	if len(salt) < MinSaltBytes {
+		return nil, ErrSaltTooShort
+	}
+	if len(salt) > MaxSaltBytes {
+		return nil, ErrSaltTooLong
+	}
+
+	d := digestPool.Get().(*digest)
+	d.salt = salt
+	d.pos = SaltSuffix
+	d.pepper = nil
+	d.maxInput = 0
+	d.enc = nil
+	d.prefix = ""
+	// ReleaseHash has already wiped the buffered data
+	d.reset()
+	return d, nil
+}
+
+// ReleaseHash returns a hash obtained from AcquireHash to the pool. The
+// hash is reset, zeroing its buffered data, and its reference to the salt
+// is dropped. The hash must not be used after it has been released, by the
+// caller or by anything the caller passed it to. Hashes not created by
+// this package are ignored.
+func ReleaseHash(h crypto.Hash) {
+	d, ok := h.(*digest)
+	if !ok {
+		return
+	}
+	if d.h != nil {
+		d.wipe()
+		d.h.Reset()
+	}
+	d.salt = nil
+	d.pos = SaltSuffix
+	d.pepper = nil
+	d.maxInput = 0
+	d.enc = nil
+	d.prefix = ""
+	d.written = 0
+	digestPool.Put(d)
+}
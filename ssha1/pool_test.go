@@ -0,0 +1,96 @@
+package ssha1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+func TestAcquireHash(t *testing.T) {
+	salt := []byte("p00lS4lt")
+	data := []byte("Well done is better than well said.")
+
+	expected, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+
+	// a released hash must come back in its initial state
+	for i := 0; i < 3; i++ {
+		h, err := AcquireHash(salt)
+		if err != nil {
+			t.Errorf("method AcquireHash() returned unexpected error: %e", err)
+			continue
+		}
+		h.Write(data)
+		if result := h.Sum(nil); !bytes.Equal(result, expected) {
+			t.Errorf("Sum result = %x; expected %x", result, expected)
+		}
+		h.Write([]byte("left over"))
+		ReleaseHash(h)
+	}
+}
+
+func TestAcquireHashErrors(t *testing.T) {
+	if _, err := AcquireHash(nil); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("AcquireHash() error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := AcquireHash([]byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("AcquireHash() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := AcquireHash(make([]byte, MaxSaltBytes+1)); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("AcquireHash() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+}
+
+func TestReleaseHashIgnoresForeignHashes(t *testing.T) {
+	h, err := crypto.NewSalted(sha256.New, []byte("abcdefg"))
+	if err != nil {
+		t.Errorf("NewSalted() returned unexpected error: %e", err)
+	}
+	ReleaseHash(h)
+	ReleaseHash(nil)
+}
+
+func TestAcquireHashConcurrent(t *testing.T) {
+	const goroutines = 16
+	const iterations = 200
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				salt := []byte(fmt.Sprintf("salt-%d-%d", g, i))
+				data := []byte(fmt.Sprintf("data-%d-%d", g, i))
+
+				h, err := AcquireHash(salt)
+				if err != nil {
+					errs <- err
+					return
+				}
+				h.Write(data)
+				sum := h.Sum(nil)
+				ReleaseHash(h)
+
+				if ok, err := Validate(sum, data); err != nil || !ok {
+					errs <- fmt.Errorf("Validate() = %t, %v for goroutine %d, iteration %d", ok, err, g, i)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
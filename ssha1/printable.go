@@ -0,0 +1,54 @@
+package ssha1
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+// NewWithPrintableSalt returns a new hash.Hash with a random salt of the
+// specified size drawn from the base-64 alphabet via the crypto/rand
+// package, for interop with tools that mishandle binary salts. Salt size
+// must be between 1 and 1024 bytes. Each salt byte carries 6 bits of
+// entropy rather than 8, so a printable salt should be about a third
+// longer than a binary one of equal strength.
+func NewWithPrintableSalt(numSaltBytes int) (crypto.Hash, error) {
+	return newWithPrintableSalt(rand.Reader, numSaltBytes)
+}
+
+// newWithPrintableSalt is NewWithPrintableSalt with the salt read from r.
+// The bytes are mapped onto the alphabet as they are read, so that the
+// check for a weak salt applies to the salt as used: distinct random bytes
+// may well map to identical characters.
+func newWithPrintableSalt(r io.Reader, numSaltBytes int) (crypto.Hash, error) {
+	return NewWithRand(printableReader{r}, numSaltBytes)
+}
+
+// printableReader maps the bytes read from r onto the base-64 alphabet.
+type printableReader struct {
+	r io.Reader
+}
+
+func (p printableReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	// 64 divides 256, so masking keeps the choice uniform
+	for i, c := range b[:n] {
+		b[i] = base64Chars[c&63]
+	}
+	return n, err
+}
+
+// IsPrintableSalt reports whether salt is non-empty and consists solely of
+// printable ASCII characters, i.e. ' ' through '~'.
+func IsPrintableSalt(salt []byte) bool {
+	if len(salt) == 0 {
+		return false
+	}
+	for _, b := range salt {
+		if b < ' ' || b > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package ssha1
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+func TestNewWithPrintableSalt(t *testing.T) {
+	for _, size := range []int{1, 8, 20, MaxSaltBytes} {
+		h, err := NewWithPrintableSalt(size)
+		if err != nil {
+			t.Errorf("method NewWithPrintableSalt() returned unexpected error: %e", err)
+		}
+
+		salt := h.(crypto.Salter).Salt()
+		if len(salt) != size {
+			t.Errorf("len(salt) = %d; expected %d", len(salt), size)
+		}
+		if !IsPrintableSalt(salt) {
+			t.Errorf("salt %q is not printable", salt)
+		}
+		for _, b := range salt {
+			if strings.IndexByte(base64Chars[:64], b) < 0 {
+				t.Errorf("salt byte %q is outside the base-64 alphabet", b)
+			}
+		}
+	}
+
+	if _, err := NewWithPrintableSalt(0); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewWithPrintableSalt(0) error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewWithPrintableSalt(MaxSaltBytes + 1); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewWithPrintableSalt() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+}
+
+// aliasedReader returns bytes that differ, but all map to the same
+// character of the base-64 alphabet.
+type aliasedReader struct{}
+
+func (aliasedReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(i%4) << 6
+	}
+	return len(p), nil
+}
+
+func TestNewWithPrintableSaltWeak(t *testing.T) {
+	// the salt is checked after mapping, so it is not let through
+	if _, err := newWithPrintableSalt(aliasedReader{}, 8); !errors.Is(err, ErrWeakSalt) {
+		t.Errorf("newWithPrintableSalt() error = %v; expected %v", err, ErrWeakSalt)
+	}
+
+	// ... but read again
+	r := io.MultiReader(io.LimitReader(aliasedReader{}, 8), rand.Reader)
+	h, err := newWithPrintableSalt(r, 8)
+	if err != nil {
+		t.Errorf("method newWithPrintableSalt() returned unexpected error: %e", err)
+	}
+	if salt := h.(crypto.Salter).Salt(); bytes.Count(salt, salt[:1]) == len(salt) {
+		t.Errorf("salt %q consists of identical bytes", salt)
+	}
+}
+
+type printableSaltCase struct {
+	salt     []byte
+	expected bool
+}
+
+func TestIsPrintableSalt(t *testing.T) {
+	printableSaltCases := []printableSaltCase{
+		{[]byte("abcdefg"), true},
+		{[]byte(" ~"), true},
+		{[]byte("n4pg+/=="), true},
+		{nil, false},
+		{[]byte{}, false},
+		{[]byte("tab\there"), false},
+		{[]byte{'a', 0x7f}, false},
+		{[]byte{'a', 0x00}, false},
+		{[]byte("café"), false},
+	}
+
+	for _, c := range printableSaltCases {
+		if result := IsPrintableSalt(c.salt); result != c.expected {
+			t.Errorf("IsPrintableSalt(%q) = %t; expected %t", c.salt, result, c.expected)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ssha1
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// propertyConfig generates a password, a wrong password and a salt for
+// each run, with lengths drawn alternately from small and large ranges so
+// that both short inputs and ones spanning many SHA-1 blocks are covered.
+func propertyConfig(seed int64) *quick.Config {
+	return &quick.Config{
+		MaxCount: 200,
+		Rand:     rand.New(rand.NewSource(seed)),
+		Values: func(args []reflect.Value, r *rand.Rand) {
+			randomBytes := func(min, max int) []byte {
+				if r.Intn(2) == 0 {
+					max = min + (max-min)/32 // small
+				}
+				b := make([]byte, min+r.Intn(max-min+1))
+				r.Read(b)
+				return b
+			}
+
+			pw := randomBytes(0, 4096)
+			wrong := randomBytes(0, 4096)
+			if bytes.Equal(wrong, pw) {
+				wrong = append(wrong, 'x')
+			}
+			args[0] = reflect.ValueOf(pw)
+			args[1] = reflect.ValueOf(wrong)
+			args[2] = reflect.ValueOf(randomBytes(MinSaltBytes, MaxSaltBytes))
+		},
+	}
+}
+
+func TestPropertyValidateSum(t *testing.T) {
+	f := func(pw, wrong, salt []byte) bool {
+		ssha1Hash, err := Sum(pw, salt)
+		if err != nil {
+			return false
+		}
+		ok, err := Validate(ssha1Hash, pw)
+		if err != nil || !ok {
+			return false
+		}
+		ok, err = Validate(ssha1Hash, wrong)
+		return err == nil && !ok
+	}
+	if err := quick.Check(f, propertyConfig(1)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyValidateStringSumString(t *testing.T) {
+	f := func(pw, wrong, salt []byte) bool {
+		encoded, err := SumString(pw, salt)
+		if err != nil {
+			return false
+		}
+		ok, err := ValidateString(encoded, pw)
+		if err != nil || !ok {
+			return false
+		}
+		ok, err = ValidateString(encoded, wrong)
+		return err == nil && !ok
+	}
+	if err := quick.Check(f, propertyConfig(2)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyDecodeSum(t *testing.T) {
+	f := func(pw, _, salt []byte) bool {
+		ssha1Hash, err := Sum(pw, salt)
+		if err != nil {
+			return false
+		}
+		sha1Part, decodedSalt, err := Decode(ssha1Hash)
+		if err != nil {
+			return false
+		}
+		ok, err := ValidateWithSalt(sha1Part, decodedSalt, pw)
+		return err == nil && ok && bytes.Equal(decodedSalt, salt)
+	}
+	if err := quick.Check(f, propertyConfig(3)); err != nil {
+		t.Error(err)
+	}
+}
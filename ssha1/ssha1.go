@@ -2,13 +2,20 @@ package ssha1
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash"
+	"io"
+	"strings"
 
 	"github.com/kristinjeanna/crypto"
 )
@@ -16,39 +23,148 @@ import (
 const (
 	// DefaultNumSaltBytes specifies the default number of salt bytes
 	// used when creating via New().
-	DefaultNumSaltBytes int = 20
+	DefaultNumSaltBytes int = crypto.DefaultNumSaltBytes
 
 	// MinSaltBytes specifies the minimum allowed number of salt bytes.
-	MinSaltBytes int = 1
+	MinSaltBytes int = crypto.MinSaltBytes
+
+	// MaxSaltBytes specifies the maximum allowed number of salt bytes.
+	MaxSaltBytes int = crypto.MaxSaltBytes
 
 	// BlockSize specifies the block size of the SHA-1 hash in bytes.
 	BlockSize = sha1.BlockSize
 
-	outputFmt string = "{SSHA}%s"
+	scheme    string = crypto.SchemeSSHA
+	outputFmt string = scheme + "%s"
+
+	// marshaled state layout: magic || version || pos || salt length (2
+	// bytes, big endian) || bytes written (8 bytes, big endian) || salt ||
+	// SHA-1 state
+	marshalMagic   string = "ssha1"
+	marshalVersion byte   = 2
+	marshalHdrLen  int    = len(marshalMagic) + 12
+
+	// size of the chunks read by SumReaderContext between checks of its
+	// context
+	sumReaderChunkSize int = 32 * 1024
+)
+
+// Errors returned by this package.
+var (
+	// ErrNilSalt is returned when NewWithSalt or NewWithSaltPosition is
+	// passed a nil salt.
+	ErrNilSalt = crypto.ErrNilSalt
+
+	// ErrSaltTooShort is returned when a salt is shorter than MinSaltBytes.
+	ErrSaltTooShort = crypto.ErrSaltTooShort
+
+	// ErrSaltTooLong is returned when a salt is longer than MaxSaltBytes.
+	ErrSaltTooLong = crypto.ErrSaltTooLong
+
+	// ErrWeakSalt is returned when the randomness source repeatedly
+	// produces salts of identical bytes.
+	ErrWeakSalt = crypto.ErrWeakSalt
+
+	// ErrSliceTooShortSHA1 is returned when a slice is too short to hold a
+	// SHA-1 hash. It is wrapped with the actual and required lengths.
+	ErrSliceTooShortSHA1 = errors.New("slice too short for a SHA-1 hash")
+
+	// ErrSliceTooShortSSHA1 is returned when a slice holds a SHA-1 hash but
+	// no salt. It is wrapped with the actual and required lengths.
+	ErrSliceTooShortSSHA1 = errors.New("slice too short to be a SSHA1 hash")
+
+	// ErrSliceTooShortSalt is returned by SumTrailingSalt when the data is
+	// shorter than the requested salt length.
+	ErrSliceTooShortSalt = errors.New("slice too short to hold the salt")
+
+	// ErrSaltBelowPolicy is returned by ValidateStrict when the salt of the
+	// stored hash is shorter than the required minimum.
+	ErrSaltBelowPolicy = errors.New("salt shorter than the policy minimum")
+
+	// ErrInvalidSHA1Size is returned when a bare SHA-1 digest is not
+	// exactly sha1.Size bytes long.
+	ErrInvalidSHA1Size = errors.New("invalid SHA-1 digest length, must be 20 bytes")
+
+	// ErrMalformedPrefix is returned when an encoded hash has a scheme prefix
+	// other than "{SSHA}".
+	ErrMalformedPrefix = errors.New("malformed scheme prefix, expected " + scheme)
+
+	// ErrInvalidBase64 is returned when an encoded hash is not valid base-64.
+	ErrInvalidBase64 = crypto.ErrInvalidBase64
+
+	// ErrEncodedHash is returned by Validate when it is passed the bytes of
+	// an encoded "{SCHEME}base64" string instead of a decoded hash.
+	ErrEncodedHash = errors.New("hash is a base64 encoded string with a scheme prefix, use ValidateString instead")
+
+	// ErrInvalidHex is returned when an encoded hash is not valid hex.
+	ErrInvalidHex = errors.New("invalid hex encoding")
+
+	// ErrInputTooLarge is returned by Write and WriteString when more data
+	// is written than allowed by SetMaxInput.
+	ErrInputTooLarge = errors.New("input exceeds the maximum set by SetMaxInput")
+
+	// ErrInvalidSaltPosition is returned when a SaltPosition is neither
+	// SaltSuffix nor SaltPrefix.
+	ErrInvalidSaltPosition = errors.New("invalid salt position")
+
+	// ErrSaltAlreadyHashed is returned by SetSalt for a digest with the salt
+	// prefixed, as the salt has already been mixed into the running hash.
+	ErrSaltAlreadyHashed = errors.New("salt already hashed, cannot replace a prefixed salt")
+
+	// ErrInvalidState is returned when unmarshaling a hash state that was
+	// not produced by MarshalBinary.
+	ErrInvalidState = errors.New("invalid hash state")
 
-	errMsgSaltTooShort       string = "invalid salt length, must be at least 1 byte"
-	errMsgSliceTooShortSha1  string = "slice too short for a SHA-1 hash"
-	errMsgSliceTooShortSsha1 string = "slice too short to be a SSHA1 hash"
+	// ErrUnsupportedStateVersion is returned when unmarshaling a hash state
+	// written by an unknown version of MarshalBinary.
+	ErrUnsupportedStateVersion = errors.New("unsupported hash state version")
 )
 
+// SaltPosition specifies where the salt is placed relative to the SHA-1
+// digest.
+type SaltPosition int
+
+const (
+	// SaltSuffix places the salt after the data when hashing and after the
+	// digest in the output: SHA1(data || salt) || salt. This is the layout
+	// used by "{SSHA}" and is the default.
+	SaltSuffix SaltPosition = iota
+
+	// SaltPrefix places the salt before the data when hashing and before
+	// the digest in the output: salt || SHA1(salt || data).
+	SaltPrefix
+)
+
+func init() {
+	crypto.Register(crypto.SchemeSSHA, Validate)
+}
+
 // New returns a new hash.Hash  with the default salt size (20 bytes).
-// The salt will be generated using the crypto/rand package.
+// The salt will be generated using the crypto/rand package. The first call
+// to New or Sum prints a deprecation notice; see SuppressDeprecationNotice.
 func New() (crypto.Hash, error) {
+	noteDeprecation()
 	d := new(digest)
 	d.Reset()
 	d.salt = make([]byte, DefaultNumSaltBytes)
-	_, err := rand.Read(d.salt)
-	if err != nil {
+	if err := crypto.ReadSalt(rand.Reader, d.salt); err != nil {
 		return nil, err
 	}
 	return d, nil
 }
 
 // NewWithSalt returns a new hash.Hash with the specified salt.
-// Salt size must be 1 or greater.
+// Salt size must be between 1 and 1024 bytes. A nil salt yields ErrNilSalt
+// and an empty one ErrSaltTooShort.
 func NewWithSalt(salt []byte) (crypto.Hash, error) {
+	if salt == nil {
+		return nil, ErrNilSalt
+	}
 	if len(salt) < MinSaltBytes {
-		return nil, errors.New(errMsgSaltTooShort)
+		return nil, ErrSaltTooShort
+	}
+	if len(salt) > MaxSaltBytes {
+		return nil, ErrSaltTooLong
 	}
 	d := new(digest)
 	d.Reset()
@@ -56,109 +172,891 @@ func NewWithSalt(salt []byte) (crypto.Hash, error) {
 	return d, nil
 }
 
+// NewWithSaltPosition returns a new hash.Hash with the specified salt,
+// placed according to pos. Salt size must be between 1 and 1024 bytes, as
+// for NewWithSalt.
+func NewWithSaltPosition(salt []byte, pos SaltPosition) (crypto.Hash, error) {
+	if salt == nil {
+		return nil, ErrNilSalt
+	}
+	if len(salt) < MinSaltBytes {
+		return nil, ErrSaltTooShort
+	}
+	if len(salt) > MaxSaltBytes {
+		return nil, ErrSaltTooLong
+	}
+	if pos != SaltSuffix && pos != SaltPrefix {
+		return nil, ErrInvalidSaltPosition
+	}
+	d := new(digest)
+	d.salt = salt
+	d.pos = pos
+	d.Reset()
+	return d, nil
+}
+
 // NewForSaltSize returns a new hash.Hash with the specified salt size.
-// Salt size must be 1 or greater. The salt will be generated using the
-// crypto/rand package.
+// Salt size must be between 1 and 1024 bytes. The salt will be generated
+// using the crypto/rand package.
 func NewForSaltSize(numSaltBytes int) (crypto.Hash, error) {
+	return NewWithRand(rand.Reader, numSaltBytes)
+}
+
+// NewWithRand returns a new hash.Hash with the specified salt size. Salt
+// size must be between 1 and 1024 bytes. The salt will be read from r,
+// allowing a randomness source other than the crypto/rand package to be
+// used. As for all generated salts, a salt of identical bytes is rejected
+// and read again; see crypto.ReadSalt.
+func NewWithRand(r io.Reader, numSaltBytes int) (crypto.Hash, error) {
+	return newWithRand(r, numSaltBytes, 0)
+}
+
+// newWithRand is NewWithRand with a budget for reading a weak salt again,
+// as for crypto.ReadSaltAttempts.
+func newWithRand(r io.Reader, numSaltBytes, attempts int) (crypto.Hash, error) {
 	if numSaltBytes < MinSaltBytes {
-		return nil, errors.New(errMsgSaltTooShort)
+		return nil, ErrSaltTooShort
+	}
+	if numSaltBytes > MaxSaltBytes {
+		return nil, ErrSaltTooLong
 	}
 	d := new(digest)
 	d.Reset()
 	d.salt = make([]byte, numSaltBytes)
-	_, err := rand.Read(d.salt)
+	if err := crypto.ReadSaltAttempts(r, d.salt, attempts); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Config specifies how NewWithConfig creates a hash. The zero value uses
+// the package defaults.
+type Config struct {
+	// SaltSize is the number of random salt bytes, between 1 and 1024. If
+	// zero, DefaultNumSaltBytes is used.
+	SaltSize int
+
+	// Rand is the source the salt is read from. If nil, the crypto/rand
+	// package is used.
+	Rand io.Reader
+
+	// SaltAttempts is the number of times a weak salt of identical bytes is
+	// read before giving up with ErrWeakSalt; see crypto.ReadSaltAttempts.
+	// If zero, the default of crypto.ReadSalt is used. With a healthy
+	// source this should essentially never matter, but it allows the
+	// budget to be tuned in constrained environments.
+	SaltAttempts int
+}
+
+// NewWithConfig returns a new hash.Hash with a random salt created as
+// specified by cfg. This allows an application to settle on non-default
+// settings once and pass them around, without mutable package state.
+func NewWithConfig(cfg Config) (crypto.Hash, error) {
+	size := cfg.SaltSize
+	if size == 0 {
+		size = DefaultNumSaltBytes
+	}
+	r := cfg.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+	return newWithRand(r, size, cfg.SaltAttempts)
+}
+
+// Options specifies how NewWithOptions creates a hash and how its String
+// method encodes the sum. The zero value uses the package defaults.
+type Options struct {
+	// Salt is the salt to use, between 1 and 1024 bytes, as for
+	// NewWithSalt. If nil, a random salt is created as specified by
+	// SaltSize and Rand, which are otherwise ignored.
+	Salt []byte
+
+	// SaltSize is the number of random salt bytes, between 1 and 1024. If
+	// zero, DefaultNumSaltBytes is used.
+	SaltSize int
+
+	// Rand is the source a random salt is read from. If nil, the
+	// crypto/rand package is used.
+	Rand io.Reader
+
+	// Encoding is the base-64 encoding used by String, e.g.
+	// base64.URLEncoding. If nil, base64.StdEncoding is used.
+	Encoding *base64.Encoding
+
+	// Prefix is the prefix used by String and returned by Scheme. If
+	// empty, "{SSHA}" is used, unless NoPrefix is set.
+	Prefix string
+
+	// NoPrefix omits the prefix from String altogether; Scheme then
+	// returns an empty string.
+	NoPrefix bool
+
+	// SaltAttempts is the number of times a weak random salt is read
+	// before giving up, as for Config.SaltAttempts.
+	SaltAttempts int
+}
+
+// NewWithOptions returns a new hash.Hash created as specified by opts,
+// whose String method, along with AppendString and StringReader, encodes
+// the sum with the encoding and prefix of opts. This gathers the choices
+// otherwise spread across the other constructors and String variants in
+// one extensible entry point.
+func NewWithOptions(opts Options) (crypto.Hash, error) {
+	var h crypto.Hash
+	var err error
+	if opts.Salt != nil {
+		h, err = NewWithSalt(opts.Salt)
+	} else {
+		h, err = NewWithConfig(Config{SaltSize: opts.SaltSize, Rand: opts.Rand, SaltAttempts: opts.SaltAttempts})
+	}
 	if err != nil {
 		return nil, err
 	}
+
+	d := h.(*digest)
+	d.enc = opts.Encoding
+	if d.enc == nil {
+		d.enc = base64.StdEncoding
+	}
+	switch {
+	case opts.NoPrefix:
+		d.prefix = ""
+	case opts.Prefix == "":
+		d.prefix = scheme
+	default:
+		d.prefix = opts.Prefix
+	}
 	return d, nil
 }
 
-// Sum returns the SSHA1 checksum of the data.
+// SumSize returns the number of bytes in a SSHA1 checksum with a salt of
+// saltLen bytes, i.e. the Size of a hash.Hash with such a salt. It can be
+// used to size buffers, e.g. for crypto.SumTo, without creating a hash.
+func SumSize(saltLen int) int {
+	return sha1.Size + saltLen
+}
+
+// Sum returns the SSHA1 checksum of the data. As for New, the first call
+// may print a deprecation notice. Empty or nil data is legal and yields
+// SHA1(salt) || salt.
 func Sum(data, salt []byte) ([]byte, error) {
-	var d hash.Hash
+	noteDeprecation()
+	if salt != nil && len(salt) >= MinSaltBytes && len(salt) <= MaxSaltBytes {
+		return sumDirect(data, salt), nil
+	}
+
+	d, err := newForSum(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Write(data)
+	return d.Sum(nil), nil
+}
+
+// sumDirect is the fast path of Sum for a given, valid salt. As the sum is
+// taken only once, it hashes straight into a SHA-1 digest and allocates
+// the output at its final size, avoiding the digest, the snapshot of its
+// state taken by digest.Sum and the regrowth of the output.
+func sumDirect(data, salt []byte) []byte {
+	h := sha1.New()
+	h.Write(data)
+	return crypto.FinishSaltedSum(make([]byte, 0, sha1.Size+len(salt)), h, nil, salt)
+}
+
+// SumEach returns the SSHA1 checksums of each of the messages with the
+// same salt, in order. The salt is validated once, as for NewWithSalt, and
+// a single SHA-1 digest is reset and reused between messages, which is
+// cheaper than calling Sum for each message. The sums share one backing
+// array, but each is capped to its own length.
+func SumEach(messages [][]byte, salt []byte) ([][]byte, error) {
 	if salt == nil {
-		d0, err := New()
-		if err != nil {
+		return nil, ErrNilSalt
+	}
+	if len(salt) < MinSaltBytes {
+		return nil, ErrSaltTooShort
+	}
+	if len(salt) > MaxSaltBytes {
+		return nil, ErrSaltTooLong
+	}
+
+	size := SumSize(len(salt))
+	buf := make([]byte, len(messages)*size)
+	sums := make([][]byte, len(messages))
+	h := sha1.New()
+	for i, m := range messages {
+		h.Reset()
+		h.Write(m)
+		sums[i] = crypto.FinishSaltedSum(buf[i*size:i*size:(i+1)*size], h, nil, salt)
+	}
+	return sums, nil
+}
+
+// SumString returns the SSHA1 checksum of the data in the "{SSHA}" prefixed
+// base-64 encoded form returned by String, ready to be stored. As with Sum,
+// a random salt is generated if salt is nil, and an empty salt yields
+// ErrSaltTooShort.
+func SumString(data, salt []byte) (string, error) {
+	d, err := newForSum(salt)
+	if err != nil {
+		return "", err
+	}
+
+	d.Write(data)
+	return d.String(), nil
+}
+
+// SumTrailingSalt returns the SSHA1 checksum of data whose last saltLen
+// bytes are the salt, as found in binary formats that embed the salt at the
+// end of the stream. The remainder of data is the message; it may be empty.
+// This splits data the same way Validate splits a SSHA1 hash.
+func SumTrailingSalt(data []byte, saltLen int) ([]byte, error) {
+	if saltLen < MinSaltBytes {
+		return nil, ErrSaltTooShort
+	}
+	if saltLen > len(data) {
+		return nil, ErrSliceTooShortSalt
+	}
+
+	msgLen := len(data) - saltLen
+	return Sum(data[:msgLen], data[msgLen:])
+}
+
+// SumReader returns the SSHA1 checksum of the data read from r until EOF.
+// As with Sum, a random salt is generated if salt is nil. The data is
+// streamed into the hash rather than read into memory first.
+func SumReader(r io.Reader, salt []byte) ([]byte, error) {
+	d, err := newForSum(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, err
+	}
+	return d.Sum(nil), nil
+}
+
+// SumReaderContext is like SumReader, but checks ctx between reads from r
+// and stops early, returning the context's error, once ctx is done. This
+// allows digesting a large stream to be abandoned, e.g. when the client of
+// a request handler disconnects. A read that is already blocked is not
+// interrupted.
+func SumReaderContext(ctx context.Context, r io.Reader, salt []byte) ([]byte, error) {
+	d, err := newForSum(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, sumReaderChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		d = d0
-	} else {
-		d0, err := NewWithSalt(salt)
+
+		n, err := r.Read(buf)
+		d.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
 			return nil, err
 		}
-		d = d0
+	}
+	return d.Sum(nil), nil
+}
+
+// newForSum returns a new hash.Hash with the specified salt or, if salt is
+// nil, a random one of the default size.
+func newForSum(salt []byte) (crypto.Hash, error) {
+	if salt == nil {
+		return New()
+	}
+	return NewWithSalt(salt)
+}
+
+// SumWithGeneratedSalt returns the SSHA1 checksum of the data using a random
+// salt of the specified size, generated via the crypto/rand package, along
+// with the salt itself.
+func SumWithGeneratedSalt(data []byte, numSaltBytes int) (sum []byte, salt []byte, err error) {
+	d, err := NewForSaltSize(numSaltBytes)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	d.Write(data)
-	return d.Sum(nil), nil
+	return d.Sum(nil), d.(crypto.Salter).Salt(), nil
 }
 
 // Validate returns true if the SSHA1 hash of the sample matches the
-// specified SSHA1 hash; false, otherwise.
+// specified SSHA1 hash; false, otherwise. The hashes are compared in
+// constant time to avoid leaking timing information. Hashes whose salt
+// would exceed MaxSaltBytes are rejected with ErrSaltTooLong before any
+// hashing is done. A common mistake is to pass the bytes of an encoded
+// string, e.g. []byte("{SSHA}..."); such input is detected and rejected
+// with ErrEncodedHash, as it should go to ValidateString instead.
+//
+// A stored hash that is corrupt, e.g. truncated or extended so that the
+// inferred salt differs from the one originally used, is not an error:
+// Validate simply returns false.
 func Validate(ssha1Hash, sample []byte) (bool, error) {
+	return ValidateWithSaltPosition(ssha1Hash, sample, SaltSuffix)
+}
+
+// maxEncodedLen is the length of the longest encoded hash isEncoded looks
+// for: "{SSHA512}" followed by the padded base-64 encoding of a SHA-512
+// digest and a salt of MaxSaltBytes. Anything longer is too large to be a
+// hash of any scheme, so it is left to the length checks of split rather
+// than copied and decoded.
+var maxEncodedLen = len(crypto.SchemeSSHA512) + base64.StdEncoding.EncodedLen(sha512.Size+MaxSaltBytes)
+
+// isEncoded reports whether b holds an encoded hash with a recognized
+// scheme prefix and a valid base-64 payload rather than a decoded hash. A
+// decoded hash may start with '{' by chance, but is vanishingly unlikely
+// to also parse as a whole.
+func isEncoded(b []byte) bool {
+	if len(b) == 0 || b[0] != '{' || len(b) > maxEncodedLen {
+		return false
+	}
+	_, _, err := crypto.ParseScheme(string(b))
+	return err == nil
+}
+
+// ValidateStrict is like Validate, but rejects a stored hash whose salt is
+// shorter than minSalt bytes with ErrSaltBelowPolicy, without hashing the
+// sample. This allows weakly salted hashes to be treated as invalid by
+// policy, e.g. during an audit, while Validate stays permissive.
+func ValidateStrict(ssha1Hash, sample []byte, minSalt int) (bool, error) {
+	_, salt, err := Decode(ssha1Hash)
+	if err != nil {
+		return false, err
+	}
+	if len(salt) < minSalt {
+		return false, fmt.Errorf("%w: got %d bytes, need %d", ErrSaltBelowPolicy, len(salt), minSalt)
+	}
+	return Validate(ssha1Hash, sample)
+}
+
+// ValidateWithSaltPosition returns true if the SSHA1 hash of the sample
+// matches the specified SSHA1 hash, with the salt placed according to pos;
+// false, otherwise. The hashes are compared in constant time.
+func ValidateWithSaltPosition(ssha1Hash, sample []byte, pos SaltPosition) (bool, error) {
+	ok, _, err := validate(ssha1Hash, sample, pos, constantTimeEqual)
+	return ok, err
+}
+
+// ValidateFunc is like Validate, but compares the hashes with eq instead of
+// in constant time; a nil eq selects the default constant-time comparison.
+// This is an advanced hook for policies requiring all comparisons to go
+// through an approved function, e.g. in FIPS mode. eq must itself be
+// constant-time, or the hook leaks timing information that Validate does
+// not.
+func ValidateFunc(ssha1Hash, sample []byte, eq func(a, b []byte) bool) (bool, error) {
+	if eq == nil {
+		eq = constantTimeEqual
+	}
+	ok, _, err := validate(ssha1Hash, sample, SaltSuffix, eq)
+	return ok, err
+}
+
+// ValidateAndSum is like Validate, but also returns the recomputed SSHA1
+// hash of the sample, i.e. Sum(sample, salt) with the salt of ssha1Hash,
+// e.g. to be kept for later comparison or logged as a fingerprint. The
+// hashes are still compared in constant time. The recomputed hash is nil
+// if an error is returned.
+func ValidateAndSum(ssha1Hash, sample []byte) (ok bool, recomputed []byte, err error) {
+	return validate(ssha1Hash, sample, SaltSuffix, constantTimeEqual)
+}
+
+// ValidateReader returns true if the SSHA1 hash of the data read from r
+// until EOF matches the specified SSHA1 hash; false, otherwise. As with
+// SumReader, the data is streamed into the hash rather than read into
+// memory first, so large candidates such as files can be validated. Errors
+// reading from r are returned. The hashes are compared in constant time.
+func ValidateReader(ssha1Hash []byte, r io.Reader) (bool, error) {
+	if isEncoded(ssha1Hash) {
+		return false, ErrEncodedHash
+	}
+
+	_, salt, err := split(ssha1Hash, SaltSuffix)
+	if err != nil {
+		return false, err
+	}
+
+	d, err := NewWithSalt(salt)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := io.Copy(d, r); err != nil {
+		return false, err
+	}
+	return constantTimeEqual(ssha1Hash, d.Sum(nil)), nil
+}
+
+// constantTimeEqual reports whether a and b are equal, comparing them in
+// constant time.
+func constantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// validate checks ssha1Hash against sample, with the salt placed according
+// to pos, comparing the hashes with eq. It also returns the recomputed
+// hash.
+func validate(ssha1Hash, sample []byte, pos SaltPosition, eq func(a, b []byte) bool) (bool, []byte, error) {
+	if isEncoded(ssha1Hash) {
+		return false, nil, ErrEncodedHash
+	}
+
+	_, salt, err := split(ssha1Hash, pos)
+	if err != nil {
+		return false, nil, err
+	}
+
+	d, err := NewWithSaltPosition(salt, pos)
+	if err != nil {
+		return false, nil, err
+	}
+
+	d.Write(sample)
+	result := d.Sum(nil)
+
+	// the salt is inferred from the length of ssha1Hash, so result has the
+	// same length; should that ever not hold, the default comparison via
+	// ConstantTimeCompare returns false for slices of unequal length rather
+	// than panicking
+	return eq(ssha1Hash, result), result, nil
+}
+
+// ValidateWithSalt returns true if the SHA-1 digest of the sample followed
+// by the salt matches the specified bare SHA-1 digest; false, otherwise.
+// This is for hashes whose salt is stored separately rather than appended
+// to the digest. The digest must be exactly sha1.Size bytes long and the
+// salt must be between 1 and 1024 bytes. The digests are compared in
+// constant time.
+func ValidateWithSalt(sha1Digest, salt, sample []byte) (bool, error) {
+	if len(sha1Digest) != sha1.Size {
+		return false, ErrInvalidSHA1Size
+	}
+
+	d, err := NewWithSalt(salt)
+	if err != nil {
+		return false, err
+	}
+
+	d.Write(sample)
+	result := d.Sum(nil)[:sha1.Size]
+
+	return subtle.ConstantTimeCompare(sha1Digest, result) == 1, nil
+}
+
+// Decode splits the specified SSHA1 hash into its 20-byte SHA-1 digest and
+// its salt. The same length rules as for Validate apply: a bare SHA-1
+// digest of exactly sha1.Size bytes yields ErrSliceTooShortSSHA1 rather
+// than an empty salt, and anything shorter ErrSliceTooShortSHA1. The
+// returned slices share memory with ssha1Hash.
+func Decode(ssha1Hash []byte) (sha1Part []byte, salt []byte, err error) {
+	return split(ssha1Hash, SaltSuffix)
+}
+
+// SaltSizeOf returns the number of salt bytes in the specified base-64
+// encoded SSHA1 hash, which is accepted in any form supported by
+// ValidateString. The result can be passed to NewForSaltSize to create a
+// new hash with the same salt size, e.g. when rotating a credential.
+func SaltSizeOf(encoded string) (int, error) {
+	ssha1Hash, err := parseString(encoded)
+	if err != nil {
+		return 0, err
+	}
+
+	_, salt, err := Decode(ssha1Hash)
+	if err != nil {
+		return 0, err
+	}
+	return len(salt), nil
+}
+
+// split separates the SHA-1 digest and salt of ssha1Hash according to pos.
+func split(ssha1Hash []byte, pos SaltPosition) (sha1Part []byte, salt []byte, err error) {
 	length := len(ssha1Hash)
 	if length < sha1.Size {
-		return false, errors.New(errMsgSliceTooShortSha1)
+		return nil, nil, fmt.Errorf("%w: got %d, need >= %d", ErrSliceTooShortSHA1, length, sha1.Size)
 	}
 
 	saltSize := length - sha1.Size
 	if saltSize == 0 {
-		return false, errors.New(errMsgSliceTooShortSsha1)
+		return nil, nil, fmt.Errorf("%w, no salt present: got %d, need >= %d", ErrSliceTooShortSSHA1, length, sha1.Size+MinSaltBytes)
+	}
+	if saltSize > MaxSaltBytes {
+		return nil, nil, ErrSaltTooLong
 	}
 
-	salt := ssha1Hash[length-saltSize:]
-	d, err := NewWithSalt(salt)
+	if pos == SaltPrefix {
+		return ssha1Hash[saltSize:], ssha1Hash[:saltSize], nil
+	}
+	return ssha1Hash[:sha1.Size], ssha1Hash[sha1.Size:], nil
+}
+
+// ValidateString returns true if the SSHA1 hash of the sample matches the
+// specified base-64 encoded SSHA1 hash; false, otherwise. The encoded hash
+// may optionally be prefixed with "{SSHA}", as produced by String(), and
+// its trailing "=" padding may be omitted. As in RFC 2307, the prefix is
+// matched case-insensitively, so e.g. "{ssha}" is accepted too.
+func ValidateString(encoded string, sample []byte) (bool, error) {
+	ssha1Hash, err := parseString(encoded)
 	if err != nil {
 		return false, err
 	}
 
-	d.Write(sample)
-	result := d.Sum(nil)
+	return Validate(ssha1Hash, sample)
+}
 
-	return bytes.Equal(ssha1Hash, result), nil
+// parseString decodes a base-64 encoded SSHA1 hash, optionally prefixed
+// with "{SSHA}" in any case.
+func parseString(encoded string) ([]byte, error) {
+	payload := encoded
+	if strings.HasPrefix(encoded, "{") {
+		if len(encoded) < len(scheme) || !strings.EqualFold(encoded[:len(scheme)], scheme) {
+			return nil, ErrMalformedPrefix
+		}
+		payload = encoded[len(scheme):]
+	}
+
+	return crypto.DecodeBase64(payload)
+}
+
+// HexValidate returns true if the SSHA1 hash of the sample matches the
+// specified hex encoded SSHA1 hash, as produced by HexString(); false,
+// otherwise. Both upper and lower case hex digits are accepted. Input of
+// odd length or containing non-hex characters is rejected with
+// ErrInvalidHex.
+func HexValidate(hexHash string, sample []byte) (bool, error) {
+	ssha1Hash, err := hex.DecodeString(hexHash)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidHex, err)
+	}
+
+	return Validate(ssha1Hash, sample)
+}
+
+// ValidateStringCT returns true if the SSHA1 hash of the sample matches the
+// stored base-64 encoded SSHA1 hash; false, otherwise. Errors in parsing
+// or decoding the stored hash are returned early, as they reveal nothing
+// secret, while the recomputed hash is compared in constant time. It is
+// equivalent to ValidateString, whose comparison is also constant-time; the
+// name makes the guarantee explicit at call sites.
+func ValidateStringCT(stored string, sample []byte) (bool, error) {
+	return ValidateString(stored, sample)
+}
+
+// Verify returns true if password matches the stored SSHA1 hash; false,
+// otherwise. The stored hash is base-64 encoded, with or without the
+// "{SSHA}" prefix. This is a convenience for the most common use of the
+// package and behaves exactly like ValidateString.
+func Verify(stored string, password []byte) (bool, error) {
+	return ValidateString(stored, password)
+}
+
+// HashPassword returns the "{SSHA}" encoded SSHA1 hash of password with a
+// random salt of DefaultNumSaltBytes bytes, ready to be stored. It is the
+// counterpart of Verify.
+func HashPassword(password []byte) (string, error) {
+	d, err := New()
+	if err != nil {
+		return "", err
+	}
+
+	d.Write(password)
+	return d.String(), nil
+}
+
+// ValidateAnyStored returns true and the index of the first matching entry
+// if the SSHA1 hash of the sample matches any of the stored base-64
+// encoded SSHA1 hashes, e.g. to reject reuse of one of a user's previous
+// passwords; otherwise, it returns false and -1. Each entry is accepted in
+// any form supported by ValidateString and compared in constant time, and
+// all entries are checked even after a match, so the time taken does not
+// reveal which entry matched. An entry that cannot be parsed or decoded
+// stops the search with an error that includes its index.
+func ValidateAnyStored(stored []string, sample []byte) (matched bool, index int, err error) {
+	index = -1
+	for i, encoded := range stored {
+		ok, err := ValidateString(encoded, sample)
+		if err != nil {
+			return false, -1, fmt.Errorf("stored hash %d: %w", i, err)
+		}
+		if ok && index < 0 {
+			index = i
+		}
+	}
+	return index >= 0, index, nil
 }
 
 // #########################################################
 
+// digest is only created by the constructors of this package, which
+// guarantee that len(salt) is between MinSaltBytes and MaxSaltBytes. The
+// pepper, if any, is only set by NewWithSaltAndPepper.
 type digest struct {
-	internal []byte
-	salt     []byte
+	h      hash.Hash
+	salt   []byte
+	pos    SaltPosition
+	pepper []byte
+
+	// maxInput is the limit set by SetMaxInput, if positive, on the number
+	// of bytes written since the last reset
+	maxInput, written int64
+
+	// enc and prefix are the encoding and prefix used by String, as set by
+	// NewWithOptions; a nil enc selects base64.StdEncoding and "{SSHA}"
+	enc    *base64.Encoding
+	prefix string
 }
 
 // Size returns the number of bytes Sum will return.
 func (d *digest) Size() int { return sha1.Size + len(d.salt) } // hash.Hash interface
 
+// SaltSize returns the number of salt bytes. Size() is always
+// sha1.Size + SaltSize().
+func (d *digest) SaltSize() int { return len(d.salt) } // crypto.Hash interface
+
+// Salt returns a copy of the salt. Modifying the returned slice does not
+// affect the digest.
+func (d *digest) Salt() []byte { // crypto.Salter interface
+	return append([]byte(nil), d.salt...)
+}
+
+// SaltUnsafe returns the salt without copying it, for callers that must
+// avoid the allocation of Salt. The returned slice aliases the digest's
+// internal state: modifying it changes the salt, corrupting any sum taken
+// afterwards, so it must be treated as read-only. Prefer Salt.
+func (d *digest) SaltUnsafe() []byte {
+	return d.salt
+}
+
+// Clone returns an independent copy of the digest, including its salt
+// and any data written so far.
+func (d *digest) Clone() crypto.Hash { // crypto.Cloner interface
+	return &digest{h: crypto.SnapshotHash(d.h, sha1.New), salt: d.Salt(), pos: d.pos, pepper: d.pepper,
+		maxInput: d.maxInput, written: d.written, enc: d.enc, prefix: d.prefix}
+}
+
 // BlockSize returns the hash's underlying block size.
 func (d *digest) BlockSize() int { return BlockSize } // hash.Hash interface
 
 // Reset resets the Hash to its initial state. The salt will remain unchanged.
+// The data buffered by the running hash, up to a block of the plaintext
+// written, is overwritten with zeros first; see Zeroize.
 func (d *digest) Reset() { // hash.Hash interface
-	d.internal = make([]byte, 0)
+	if d.h != nil {
+		d.wipe()
+	}
+	d.reset()
+}
+
+// reset is Reset without overwriting the buffered data, for digests reused
+// internally where the cost of wiping would dominate, e.g. by
+// Validator.Check.
+func (d *digest) reset() {
+	d.written = 0
+	if d.h == nil {
+		d.h = sha1.New()
+	} else {
+		d.h.Reset()
+	}
+	if d.pos == SaltPrefix {
+		d.h.Write(d.salt)
+	}
+}
+
+// Zeroize overwrites the data buffered by the running hash with zeros and
+// resets it, as Reset does, so that a password just hashed does not linger
+// in memory until the digest is reused or collected. The name makes the
+// intent explicit at call sites. Copies of the state taken by Sum, Clone
+// and MarshalBinary are not affected.
+func (d *digest) Zeroize() {
+	d.Reset()
+}
+
+// zeroBlock is written by wipe to overwrite the block buffer of the running
+// hash.
+var zeroBlock [BlockSize]byte
+
+// wipe overwrites the block buffer of the running hash with zeros. The
+// buffer holds the trailing partial block of the bytes hashed, so a block
+// of zeros written on top passes through all of it. If the bytes hashed
+// end on a block boundary, the buffer may still hold the last block it
+// assembled, which a single zero byte followed by the rest of a block
+// overwrites. Nothing is written if no data has been written since the
+// last reset.
+func (d *digest) wipe() {
+	if d.written == 0 {
+		return
+	}
+	n := d.written
+	if d.pos == SaltPrefix {
+		n += int64(len(d.salt))
+	}
+	if n%BlockSize != 0 {
+		d.h.Write(zeroBlock[:])
+		return
+	}
+	d.h.Write(zeroBlock[:1])
+	d.h.Write(zeroBlock[1:])
+}
+
+// ResetWithNewSalt resets the Hash to its initial state and replaces the
+// salt with a new random one of the same size, generated via the
+// crypto/rand package. The salt position is unchanged. A digest with no
+// salt yields ErrSaltTooShort, as there is no size to preserve.
+func (d *digest) ResetWithNewSalt() error { // crypto.SaltResetter interface
+	if len(d.salt) == 0 {
+		return ErrSaltTooShort
+	}
+	salt := make([]byte, len(d.salt))
+	if err := crypto.ReadSalt(rand.Reader, salt); err != nil {
+		return err
+	}
+	d.salt = salt
+	d.Reset()
+	return nil
+}
+
+// SetSalt replaces the salt while keeping the data written so far, so that
+// the next Sum covers the same data with the new salt. The salt is copied
+// and its size must be between 1 and 1024 bytes; a nil salt yields
+// ErrNilSalt. As a prefixed salt has already been hashed along with the
+// data, SetSalt returns ErrSaltAlreadyHashed for a digest using SaltPrefix.
+func (d *digest) SetSalt(salt []byte) error {
+	if salt == nil {
+		return ErrNilSalt
+	}
+	if len(salt) < MinSaltBytes {
+		return ErrSaltTooShort
+	}
+	if len(salt) > MaxSaltBytes {
+		return ErrSaltTooLong
+	}
+	if d.pos == SaltPrefix {
+		return ErrSaltAlreadyHashed
+	}
+	d.salt = append([]byte(nil), salt...)
+	return nil
+}
+
+// SetMaxInput limits the number of bytes that may be written to the digest
+// until it is next reset to n. Once the limit is reached, Write and
+// WriteString hash only the bytes that still fit and return
+// ErrInputTooLarge, guarding against unbounded input when hashing
+// untrusted data. A limit of 0 or less, the default, removes the limit.
+func (d *digest) SetMaxInput(n int) {
+	d.maxInput = int64(n)
+}
+
+// allowance returns how many of n further bytes may be written. It is
+// never negative, even if the limit was lowered below the bytes already
+// written.
+func (d *digest) allowance(n int) int {
+	if d.maxInput <= 0 {
+		return n
+	}
+	remaining := d.maxInput - d.written
+	if remaining < 0 {
+		return 0
+	}
+	if int64(n) > remaining {
+		return int(remaining)
+	}
+	return n
 }
 
 // Write adds more data to the running hash.
-// It never returns an error.
+// It only returns an error if a limit set by SetMaxInput is exceeded.
 func (d *digest) Write(p []byte) (int, error) { // io.Writer interface
-	d.internal = append(d.internal, p...)
-	return len(p), nil
+	allowed := d.allowance(len(p))
+	n, _ := d.h.Write(p[:allowed])
+	d.written += int64(n)
+	if allowed < len(p) {
+		return n, ErrInputTooLarge
+	}
+	return n, nil
+}
+
+// WriteString adds the bytes of s to the running hash without requiring a
+// []byte conversion by the caller.
+// It only returns an error if a limit set by SetMaxInput is exceeded.
+func (d *digest) WriteString(s string) (int, error) { // io.StringWriter interface
+	allowed := d.allowance(len(s))
+	var n int
+	if sw, ok := d.h.(io.StringWriter); ok {
+		n, _ = sw.WriteString(s[:allowed])
+	} else {
+		n, _ = d.h.Write([]byte(s[:allowed]))
+	}
+	d.written += int64(n)
+	if allowed < len(s) {
+		return n, ErrInputTooLarge
+	}
+	return n, nil
 }
 
 // Sum appends the current hash to b and returns the resulting slice.
-// It does not change the underlying hash state.
+// It does not change the underlying hash state: Sum does not finalize the
+// hash, and further writes extend the data hashed so far, so Write and Sum
+// may be interleaved freely. Sum panics if the digest has no salt, rather
+// than silently produce an unsalted SHA-1 sum; the constructors never
+// return such a digest.
 func (d *digest) Sum(in []byte) []byte { // hash.Hash interface
-	tmp := d.internal
-	tmp = append(tmp, d.salt...)
-	sum := sha1.Sum(tmp)
-	tmp = append(sum[:], d.salt...)
-	return append(in, tmp...)
+	if len(d.salt) == 0 {
+		panic("ssha1: Sum called on a digest with no salt")
+	}
+	if d.pos == SaltPrefix {
+		in = append(in, d.salt...)
+		return crypto.SnapshotHash(d.h, sha1.New).Sum(in)
+	}
+	return crypto.AppendSaltedSum(in, d.h, sha1.New, d.pepper, d.salt)
+}
+
+// Scheme returns the scheme prefix used by String, "{SSHA}", or the prefix
+// set by the options of a hash created by NewWithOptions, which is empty
+// if NoPrefix is set.
+func (d *digest) Scheme() string { // crypto.Hash interface
+	_, prefix := d.stringFormat()
+	return prefix
 }
 
 // String returns the base-64 encoded string representation of
-// the SSHA1 sum, prefixed with "{SSHA}".
+// the SSHA1 sum, prefixed with "{SSHA}", or as specified by the options of
+// a hash created by NewWithOptions.
 func (d *digest) String() string { // fmt.Stringer interface
+	enc, prefix := d.stringFormat()
+	return prefix + enc.EncodeToString(d.Sum(nil))
+}
+
+// stringFormat returns the encoding and prefix used by String.
+func (d *digest) stringFormat() (*base64.Encoding, string) {
+	if d.enc == nil {
+		return base64.StdEncoding, scheme
+	}
+	return d.enc, d.prefix
+}
+
+// StringWithPrefix returns the base-64 encoded string representation of
+// the SSHA1 sum, prefixed with the specified prefix instead of "{SSHA}".
+// This is useful for tools that expect e.g. a lowercase scheme or no
+// prefix at all.
+func (d *digest) StringWithPrefix(prefix string) string { // crypto.PrefixStringer interface
+	sum := d.Sum(nil)
+	return prefix + base64.StdEncoding.EncodeToString(sum)
+}
+
+// URLString returns the base-64 encoded string representation of the SSHA1
+// sum using the URL and filename safe alphabet (RFC 4648), prefixed with
+// "{SSHA}". Where String uses '+' and '/', URLString uses '-' and '_', so
+// the two forms must not be mixed when validating.
+func (d *digest) URLString() string { // crypto.URLStringer interface
 	sum := d.Sum(nil)
-	return fmt.Sprintf(outputFmt, base64.StdEncoding.EncodeToString(sum))
+	return fmt.Sprintf(outputFmt, base64.URLEncoding.EncodeToString(sum))
 }
 
 // HexString returns the SSHA1 sum as a hexadecimal string
@@ -166,3 +1064,116 @@ func (d *digest) HexString() string { // crypto.Hash interface
 	sum := d.Sum(nil)
 	return hex.EncodeToString(sum)
 }
+
+// WriteTo writes the raw sum, as returned by Sum(nil), to w. It returns the
+// number of bytes written and any error returned by w.
+func (d *digest) WriteTo(w io.Writer) (int64, error) { // io.WriterTo interface
+	n, err := w.Write(d.Sum(nil))
+	return int64(n), err
+}
+
+// AppendString appends the string representation returned by String to dst
+// and returns the extended slice. Reusing dst across calls avoids the
+// allocations of String, e.g. when logging many hashes. As the constructors
+// return a crypto.Hash, it is reached via an interface assertion:
+//
+//	buf = h.(interface{ AppendString([]byte) []byte }).AppendString(buf[:0])
+func (d *digest) AppendString(dst []byte) []byte {
+	enc, prefix := d.stringFormat()
+	size := d.Size()
+	encLen := enc.EncodedLen(size)
+	dst = grow(dst, len(prefix)+encLen+size)
+	dst = append(dst, prefix...)
+
+	// the raw sum is placed just past the room for its encoding, so that it
+	// can be encoded in place without an intermediate buffer
+	n := len(dst)
+	buf := d.Sum(dst[:n+encLen])
+	enc.Encode(buf[n:n+encLen], buf[n+encLen:])
+	return buf[:n+encLen]
+}
+
+// StringReader returns a reader of the string representation returned by
+// String, e.g. for copying it into an HTTP response or a file with io.Copy.
+// The sum is taken when StringReader is called; data written afterwards is
+// not reflected.
+func (d *digest) StringReader() io.Reader {
+	return bytes.NewReader(d.AppendString(nil))
+}
+
+// grow returns b with room for at least n more bytes beyond its length.
+func grow(b []byte, n int) []byte {
+	if cap(b)-len(b) >= n {
+		return b
+	}
+	nb := make([]byte, len(b), len(b)+n)
+	copy(nb, b)
+	return nb
+}
+
+// MarshalBinary encodes the salt, salt position, the number of bytes
+// written since the last reset and the running hash state so that the
+// computation can be resumed later via UnmarshalBinary. The digest itself
+// is unaffected and may still be written to; a digest restored from the
+// state continues exactly where the original stood when it was marshaled. A digest with no salt yields ErrSaltTooShort, and one
+// with a pepper ErrPepperedState, as the pepper must not be persisted.
+func (d *digest) MarshalBinary() ([]byte, error) { // encoding.BinaryMarshaler interface
+	if len(d.salt) == 0 {
+		return nil, ErrSaltTooShort
+	}
+	if d.pepper != nil {
+		return nil, ErrPepperedState
+	}
+	state, err := d.h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 0, marshalHdrLen+len(d.salt)+len(state))
+	b = append(b, marshalMagic...)
+	b = append(b, marshalVersion, byte(d.pos))
+	b = append(b, byte(len(d.salt)>>8), byte(len(d.salt)))
+	var written [8]byte
+	binary.BigEndian.PutUint64(written[:], uint64(d.written))
+	b = append(b, written[:]...)
+	b = append(b, d.salt...)
+	return append(b, state...), nil
+}
+
+// UnmarshalBinary restores a hash state previously encoded by
+// MarshalBinary, replacing the salt, any pepper and any data written so
+// far. The restored byte count carries on towards the limit set by
+// SetMaxInput, which is kept.
+func (d *digest) UnmarshalBinary(b []byte) error { // encoding.BinaryUnmarshaler interface
+	if len(b) < marshalHdrLen || string(b[:len(marshalMagic)]) != marshalMagic {
+		return ErrInvalidState
+	}
+	b = b[len(marshalMagic):]
+	if b[0] != marshalVersion {
+		return ErrUnsupportedStateVersion
+	}
+
+	pos := SaltPosition(b[1])
+	if pos != SaltSuffix && pos != SaltPrefix {
+		return ErrInvalidSaltPosition
+	}
+
+	saltSize := int(binary.BigEndian.Uint16(b[2:]))
+	written := int64(binary.BigEndian.Uint64(b[4:]))
+	b = b[12:]
+	if saltSize < MinSaltBytes || saltSize > MaxSaltBytes || len(b) < saltSize || written < 0 {
+		return ErrInvalidState
+	}
+
+	h := sha1.New()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(b[saltSize:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidState, err)
+	}
+
+	d.h = h
+	d.salt = append([]byte(nil), b[:saltSize]...)
+	d.pos = pos
+	d.pepper = nil
+	d.written = written
+	return nil
+}
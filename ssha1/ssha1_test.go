@@ -1,10 +1,23 @@
 package ssha1
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
 	"crypto/sha1"
+	"encoding"
+	"encoding/base64"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"hash"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
+
+	"github.com/kristinjeanna/crypto"
 )
 
 type sumCase struct {
@@ -43,6 +56,33 @@ func TestSum(t *testing.T) {
 	}
 }
 
+func TestSumFastPath(t *testing.T) {
+	for _, data := range [][]byte{{}, []byte("12345678"), bytes.Repeat([]byte("eyJhbGciOiJIUzI1NiJ9"), 50)} {
+		for size := 1; size <= 32; size++ {
+			salt := bytes.Repeat([]byte{'s'}, size)
+			salt[0] = byte(size)
+
+			result, err := Sum(data, salt)
+			if err != nil {
+				t.Errorf("method Sum() returned unexpected error: %e", err)
+			}
+
+			// the general path via a digest
+			h, err := NewWithSalt(salt)
+			if err != nil {
+				t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+			}
+			h.Write(data)
+			if expected := h.Sum(nil); !bytes.Equal(result, expected) {
+				t.Errorf("Sum(salt size %d) = %x; expected %x", size, result, expected)
+			}
+			if len(result) != cap(result) {
+				t.Errorf("Sum() capacity = %d; expected %d", cap(result), len(result))
+			}
+		}
+	}
+}
+
 type sizeCase struct {
 	newMethod   string
 	h           hash.Hash
@@ -142,6 +182,249 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateAndSum(t *testing.T) {
+	// salt: "abcdefg"
+	stored, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+	_, salt, err := Decode(stored)
+	if err != nil {
+		t.Errorf("method Decode() returned unexpected error: %e", err)
+	}
+
+	for _, c := range []validateCase{
+		{"", []byte("1234567890"), true, false},
+		{"", []byte("123456789"), false, false},
+		{"", []byte{}, false, false},
+	} {
+		ok, recomputed, err := ValidateAndSum(stored, c.sample)
+		if err != nil {
+			t.Errorf("method ValidateAndSum() returned unexpected error: %e", err)
+		}
+		if ok != c.expected {
+			t.Errorf("ValidateAndSum(%q) = %t; expected %t", c.sample, ok, c.expected)
+		}
+
+		expected, err := Sum(c.sample, salt)
+		if err != nil {
+			t.Errorf("method Sum() returned unexpected error: %e", err)
+		}
+		if !bytes.Equal(recomputed, expected) {
+			t.Errorf("ValidateAndSum(%q) recomputed = %x; expected %x", c.sample, recomputed, expected)
+		}
+		if ok != bytes.Equal(recomputed, stored) {
+			t.Errorf("ValidateAndSum(%q) = %t, inconsistent with its recomputed hash", c.sample, ok)
+		}
+	}
+
+	ok, recomputed, err := ValidateAndSum(stored[:20], nil)
+	if !errors.Is(err, ErrSliceTooShortSSHA1) || ok || recomputed != nil {
+		t.Errorf("ValidateAndSum() = %t, %x, %v; expected false, nil, %v", ok, recomputed, err, ErrSliceTooShortSSHA1)
+	}
+}
+
+// errReader returns the data of r, then err instead of io.EOF.
+type errReader struct {
+	r   io.Reader
+	err error
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err == io.EOF {
+		return n, e.err
+	}
+	return n, err
+}
+
+func TestValidateReader(t *testing.T) {
+	salt := []byte("rE4d3rXy")
+	data := []byte(strings.Repeat("All that glitters is not gold. ", 64*1024))
+	stored, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+
+	for _, candidate := range [][]byte{data, data[:len(data)-1], append(append([]byte(nil), data...), '!')} {
+		expected, err := Validate(stored, candidate)
+		if err != nil {
+			t.Errorf("method Validate() returned unexpected error: %e", err)
+		}
+		result, err := ValidateReader(stored, bytes.NewReader(candidate))
+		if err != nil {
+			t.Errorf("method ValidateReader() returned unexpected error: %e", err)
+		}
+		if result != expected {
+			t.Errorf("ValidateReader() = %t; Validate returned %t", result, expected)
+		}
+	}
+	if result, _ := ValidateReader(stored, bytes.NewReader(data)); !result {
+		t.Errorf("ValidateReader() = false for the original data; expected true")
+	}
+
+	readErr := errors.New("read failed")
+	if _, err := ValidateReader(stored, &errReader{bytes.NewReader(data), readErr}); !errors.Is(err, readErr) {
+		t.Errorf("ValidateReader() error = %v; expected %v", err, readErr)
+	}
+	if _, err := ValidateReader(stored[:20], bytes.NewReader(data)); !errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("ValidateReader() error = %v; expected %v", err, ErrSliceTooShortSSHA1)
+	}
+}
+
+func TestValidateFunc(t *testing.T) {
+	// salt: "abcdefg"
+	stored, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+
+	calls := 0
+	eq := func(a, b []byte) bool {
+		calls++
+		return bytes.Equal(a, b)
+	}
+	for _, c := range []validateCase{
+		{"", []byte("1234567890"), true, false},
+		{"", []byte("123456789"), false, false},
+	} {
+		result, err := ValidateFunc(stored, c.sample, eq)
+		if err != nil {
+			t.Errorf("method ValidateFunc() returned unexpected error: %e", err)
+		}
+		if result != c.expected {
+			t.Errorf("ValidateFunc(%q) = %t; expected %t", c.sample, result, c.expected)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("comparator invoked %d times; expected 2", calls)
+	}
+
+	// the comparator's verdict is final
+	never := func(a, b []byte) bool { return false }
+	if result, err := ValidateFunc(stored, []byte("1234567890"), never); err != nil || result {
+		t.Errorf("ValidateFunc() = %t, %v; expected false, nil", result, err)
+	}
+
+	// nil selects the default comparison
+	if result, err := ValidateFunc(stored, []byte("1234567890"), nil); err != nil || !result {
+		t.Errorf("ValidateFunc() = %t, %v; expected true, nil", result, err)
+	}
+
+	// the comparator is not invoked for malformed input
+	calls = 0
+	if _, err := ValidateFunc(stored[:20], nil, eq); !errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("ValidateFunc() error = %v; expected %v", err, ErrSliceTooShortSSHA1)
+	}
+	if calls != 0 {
+		t.Errorf("comparator invoked %d times for malformed input; expected 0", calls)
+	}
+}
+
+func TestValidateSaltLengthMismatch(t *testing.T) {
+	// salt: "n4pggXWL"
+	stored, err := hex.DecodeString("8eadde532169b6908034886be119c9f0ca61801e6e3470676758574c")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+	plaintext := []byte("supercalifragilisticexpialidocious")
+
+	corrupt := map[string][]byte{
+		"truncated salt": stored[:len(stored)-1],
+		"extended salt":  append(append([]byte(nil), stored...), 'x'),
+		"doubled salt":   append(append([]byte(nil), stored...), "n4pggXWL"...),
+	}
+	for name, ssha1Hash := range corrupt {
+		result, err := Validate(ssha1Hash, plaintext)
+		if err != nil {
+			t.Errorf("Validate() returned unexpected error for %s: %e", name, err)
+		}
+		if result {
+			t.Errorf("Validate() = true for %s; expected false", name)
+		}
+	}
+
+	// the salt of a hash created with a prefixed salt is misread
+	prefixed, err := NewWithSaltPosition([]byte("n4pggXWL"), SaltPrefix)
+	if err != nil {
+		t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+	}
+	prefixed.Write(plaintext)
+	if result, err := Validate(prefixed.Sum(nil), plaintext); err != nil || result {
+		t.Errorf("Validate() = %t, %v for a prefixed salt; expected false, nil", result, err)
+	}
+}
+
+func TestValidateEncodedHash(t *testing.T) {
+	for _, encoded := range []string{
+		"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn",
+		"{ssha}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn",
+		"{SSHA256}iKAPRoNs1inQt53phTKv3jrq15pcU+SEgQL0MwRtAQZhYmNkZWZn",
+	} {
+		result, err := Validate([]byte(encoded), []byte("1234567890"))
+		if !errors.Is(err, ErrEncodedHash) {
+			t.Errorf("Validate(%q) error = %v; expected %v", encoded, err, ErrEncodedHash)
+		}
+		if result {
+			t.Errorf("Validate(%q) = true; expected false", encoded)
+		}
+	}
+
+	// a decoded hash that happens to start with '{' is still validated
+	salt := []byte("abcdefg")
+	for i := 0; i < 1<<16; i++ {
+		sample := []byte(fmt.Sprintf("sample %d", i))
+		ssha1Hash, err := Sum(sample, salt)
+		if err != nil {
+			t.Fatalf("method Sum() returned unexpected error: %e", err)
+		}
+		if ssha1Hash[0] != '{' {
+			continue
+		}
+		if result, err := Validate(ssha1Hash, sample); err != nil || !result {
+			t.Errorf("Validate(%x) = %t, %v; expected true, nil", ssha1Hash, result, err)
+		}
+		break
+	}
+}
+
+type validateStrictCase struct {
+	ssha1HashString string
+	sample          []byte
+	minSalt         int
+	expected        bool
+	expectedErr     error
+}
+
+func TestValidateStrict(t *testing.T) {
+	cases := []validateStrictCase{
+		// salt: "X"
+		{"691beaac130a0be25dc517de4e6391334d3d0f3758", []byte("protean-pith-anodyne-accolade-snare"), 8, false, ErrSaltBelowPolicy},
+		{"691beaac130a0be25dc517de4e6391334d3d0f3758", []byte("protean-pith-anodyne-accolade-snare"), 1, true, nil},
+		// salt: "n4pggXWL"
+		{"8eadde532169b6908034886be119c9f0ca61801e6e3470676758574c", []byte("supercalifragilisticexpialidocious"), 8, true, nil},
+		{"8eadde532169b6908034886be119c9f0ca61801e6e3470676758574c", []byte("supercalifragilisticexpialidociou"), 8, false, nil},
+		{"8eadde532169b6908034886be119c9f0ca61801e6e3470676758574c", []byte("supercalifragilisticexpialidocious"), 9, false, ErrSaltBelowPolicy},
+		// lacks at least 1 salt byte
+		{"9ab50f27d4201db9b28483ba83c48ebafbb2aa17", nil, 8, false, ErrSliceTooShortSSHA1},
+	}
+
+	for _, c := range cases {
+		ssha1Hash, err := hex.DecodeString(c.ssha1HashString)
+		if err != nil {
+			t.Errorf("unable to convert hex string '%s' to []byte.", err)
+		}
+
+		result, err := ValidateStrict(ssha1Hash, c.sample, c.minSalt)
+		if !errors.Is(err, c.expectedErr) {
+			t.Errorf("ValidateStrict() error = %v; expected %v for test case: %v", err, c.expectedErr, c)
+		}
+		if result != c.expected {
+			t.Errorf("validation test failed for test case %v", c)
+		}
+	}
+}
+
 func TestBlockSize(t *testing.T) {
 	c, err := New()
 	if err != nil {
@@ -181,3 +464,2245 @@ func TestString(t *testing.T) {
 		t.Errorf("String result = %s; expected %s", result, expected)
 	}
 }
+
+func TestEmptyMessage(t *testing.T) {
+	salt := []byte("3mp7yS4l7")
+
+	// SHA1(salt) || salt
+	expectedHex := "90decae8b75f0b7774874b13fdc372f302c9689a336d70377953346c37"
+	expectedString := "{SSHA}kN7K6LdfC3d0h0sT/cNy8wLJaJozbXA3eVM0bDc="
+	expected, err := hex.DecodeString(expectedHex)
+	if err != nil {
+		t.Errorf("unexpected error decoding hex string: %e", err)
+	}
+
+	for _, data := range [][]byte{nil, {}} {
+		result, err := Sum(data, salt)
+		if err != nil {
+			t.Errorf("method Sum() returned unexpected error: %e", err)
+		}
+		if !bytes.Equal(result, expected) {
+			t.Errorf("Sum(%#v) = %x; expected %s", data, result, expectedHex)
+		}
+
+		valid, err := Validate(expected, data)
+		if err != nil {
+			t.Errorf("method Validate() returned unexpected error: %e", err)
+		}
+		if !valid {
+			t.Errorf("Validate(%#v) = false; expected true", data)
+		}
+	}
+
+	if valid, _ := Validate(expected, []byte(" ")); valid {
+		t.Errorf("Validate of a non-empty sample = true; expected false")
+	}
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	if result := c.String(); result != expectedString {
+		t.Errorf("String result with nothing written = %s; expected %s", result, expectedString)
+	}
+	c.Write(nil)
+	c.(io.StringWriter).WriteString("")
+	if result := c.HexString(); result != expectedHex {
+		t.Errorf("HexString result after empty writes = %s; expected %s", result, expectedHex)
+	}
+}
+
+func TestSumDoesNotChangeState(t *testing.T) {
+	salt := []byte("tH3g5qLx")
+	first := []byte("The quick brown fox ")
+	second := []byte("jumps over the lazy dog.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write(first)
+	sum1 := c.Sum(nil)
+	c.Write(second)
+	sum2 := c.Sum(nil)
+
+	expected1, err := Sum(first, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(sum1, expected1) {
+		t.Errorf("first Sum result = %x; expected %x", sum1, expected1)
+	}
+
+	expected2, err := Sum(append(append([]byte{}, first...), second...), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(sum2, expected2) {
+		t.Errorf("second Sum result = %x; expected %x", sum2, expected2)
+	}
+}
+
+func TestWriteAfterSum(t *testing.T) {
+	salt := []byte("tH3g5qLx")
+	data := []byte("Nothing in life is to be feared, it is only to be understood.")
+
+	for _, pos := range []SaltPosition{SaltSuffix, SaltPrefix} {
+		expected, err := NewWithSaltPosition(salt, pos)
+		if err != nil {
+			t.Fatalf("method NewWithSaltPosition() returned unexpected error: %e", err)
+		}
+		expected.Write(data)
+
+		for i := 0; i <= len(data); i++ {
+			c, err := NewWithSaltPosition(salt, pos)
+			if err != nil {
+				t.Fatalf("method NewWithSaltPosition() returned unexpected error: %e", err)
+			}
+
+			// a buffer with spare capacity must not alias the hash state
+			buf := make([]byte, 0, 2*c.Size())
+			c.Write(data[:i])
+			buf = c.Sum(buf)
+			c.Write(data[i:])
+
+			if result := c.Sum(buf[:0]); !bytes.Equal(result, expected.Sum(nil)) {
+				t.Errorf("Sum after split at %d with %v = %x; expected %x", i, pos, result, expected.Sum(nil))
+			}
+		}
+	}
+}
+
+func TestSumIsRepeatable(t *testing.T) {
+	c, err := NewWithSalt([]byte("9vQe2LmR"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("Nothing in life is to be feared, it is only to be understood."))
+
+	sum1 := c.Sum(nil)
+	sum2 := c.Sum(nil)
+	if !bytes.Equal(sum1, sum2) {
+		t.Errorf("repeated Sum results differ: %x and %x", sum1, sum2)
+	}
+}
+
+func TestWriteInChunks(t *testing.T) {
+	salt := []byte("Zp3kW8sN")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1024)
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		c.Write(data[i:end])
+	}
+
+	expected, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("chunked Sum result = %x; expected %x", result, expected)
+	}
+}
+
+func TestReset(t *testing.T) {
+	salt := []byte("Zp3kW8sN")
+	data := []byte("Simplicity is the ultimate sophistication.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("discarded"))
+	c.Reset()
+	c.Write(data)
+
+	expected, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result after Reset = %x; expected %x", result, expected)
+	}
+}
+
+// blockBuffer returns the block buffer of the SHA-1 hash underlying c, as
+// held by the crypto/sha1 implementation, skipping the test if it has none.
+func blockBuffer(t *testing.T, c crypto.Hash) reflect.Value {
+	h := reflect.ValueOf(c.(*digest).h)
+	if h.Kind() != reflect.Ptr || h.Elem().Kind() != reflect.Struct {
+		t.Skipf("unexpected SHA-1 implementation %T", c.(*digest).h)
+	}
+	x := h.Elem().FieldByName("x")
+	if !x.IsValid() || x.Kind() != reflect.Array {
+		t.Skipf("SHA-1 implementation %T has no block buffer", c.(*digest).h)
+	}
+	return x
+}
+
+// bufferHolds reports whether the block buffer x contains b.
+func bufferHolds(x reflect.Value, b []byte) bool {
+	buf := make([]byte, x.Len())
+	for i := range buf {
+		buf[i] = byte(x.Index(i).Uint())
+	}
+	return bytes.Contains(buf, b)
+}
+
+func TestZeroize(t *testing.T) {
+	salt := []byte("Zp3kW8sN")
+	// each secret is written in the chunks given
+	secrets := [][][]byte{
+		{[]byte("s3cr3t p4ssw0rd")},
+		// a full block followed by a partial one
+		{[]byte(strings.Repeat("0123456789", 7) + "s3cr3t p4ssw0rd")},
+		// a block filled in two writes, leaving a stale but full buffer
+		{[]byte("s3cr3t p4ssw0rd!"), []byte(strings.Repeat("s3cr3t p4ssw0rd!", 3))},
+	}
+
+	for _, reset := range []func(c crypto.Hash){
+		func(c crypto.Hash) { c.(*digest).Zeroize() },
+		func(c crypto.Hash) { c.Reset() },
+	} {
+		for _, secret := range secrets {
+			c, err := NewWithSalt(salt)
+			if err != nil {
+				t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+				continue
+			}
+			x := blockBuffer(t, c)
+
+			for _, chunk := range secret {
+				c.Write(chunk)
+			}
+			if !bufferHolds(x, []byte("s3cr3t")) {
+				t.Errorf("block buffer does not hold the plaintext written; the test cannot observe zeroing")
+			}
+
+			reset(c)
+			for i := 0; i < x.Len(); i++ {
+				if x.Index(i).Uint() != 0 {
+					t.Errorf("block buffer after reset = % x; expected all zeros", x)
+					break
+				}
+			}
+
+			// the digest is still usable
+			c.Write([]byte("data"))
+			if result, expected := c.Sum(nil), sumDirect([]byte("data"), salt); !bytes.Equal(result, expected) {
+				t.Errorf("Sum result after reset = %x; expected %x", result, expected)
+			}
+		}
+	}
+}
+
+func TestZeroizeSaltPrefix(t *testing.T) {
+	// the prefixed salt counts towards the block: 8 + 16 + 5*8 bytes end on
+	// a block boundary, 8 + 16 + 2*8 in the middle of one
+	for _, repeats := range []int{5, 2} {
+		c, err := NewWithSaltPosition([]byte("Zp3kW8sN"), SaltPrefix)
+		if err != nil {
+			t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+			continue
+		}
+		x := blockBuffer(t, c)
+
+		c.Write([]byte("s3cr3t p4ssw0rd!"))
+		c.Write(bytes.Repeat([]byte("s3cr3t!!"), repeats))
+		if !bufferHolds(x, []byte("s3cr3t")) {
+			t.Errorf("block buffer does not hold the plaintext written; the test cannot observe zeroing")
+		}
+
+		// Reset writes the salt again, but no plaintext remains
+		c.(*digest).Zeroize()
+		if bufferHolds(x, []byte("s3cr3t")) {
+			t.Errorf("block buffer after Zeroize = % x; expected no plaintext", x)
+		}
+	}
+}
+
+func TestValidateCandidates(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	stored, err := Sum(password, []byte("u7Yb1xQc"))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+
+	if result, err := Validate(stored, password); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+
+	wrong := []byte("correct horse battery stapler")
+	if result, err := Validate(stored, wrong); err != nil || result {
+		t.Errorf("Validate() = %t, %v; expected false, nil", result, err)
+	}
+}
+
+type validateStringCase struct {
+	encoded     string
+	sample      []byte
+	expected    bool
+	expectError bool
+}
+
+func TestValidateString(t *testing.T) {
+	cases := []validateStringCase{
+		// salt: "R*w.5Vmo"
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), true, false},
+		// salt: "R*w.5Vmo", no prefix
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), true, false},
+		// salt: "R*w.5Vmo", wrong sample
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number two."), false, false},
+		// salt: "R*w.5Vmo", padding stripped
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw", []byte("You have to be odd to be number one."), true, false},
+		// salt: "R*w.5Vmo", padding stripped, no prefix
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw", []byte("You have to be odd to be number one."), true, false},
+		// salt: "R*w.5Vmo", padding stripped, wrong sample
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw", []byte("You have to be odd to be number two."), false, false},
+		// partial padding
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=", []byte("You have to be odd to be number one."), false, true},
+		// unknown scheme prefix
+		{"{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), false, true},
+		// unterminated scheme prefix
+		// the prefix is matched case-insensitively
+		{"{ssha}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), true, false},
+		{"{SsHa}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number two."), false, false},
+		{"{SSHA256}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), false, true},
+		{"{SSH", nil, false, true},
+		{"{SSHAh+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), false, true},
+		// garbage base64
+		{"{SSHA}not*valid*base64!", []byte("You have to be odd to be number one."), false, true},
+		// valid base64, but too short to be a SSHA1 hash
+		{"{SSHA}UgpBsp+JG7rM8x0=", nil, false, true},
+	}
+
+	for _, c := range cases {
+		result, err := ValidateString(c.encoded, c.sample)
+		if c.expectError {
+			if err == nil {
+				t.Errorf("expected error but none returned for test case: %v", c)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("validation test failed for test case %v", c)
+		}
+	}
+}
+
+func TestSaltSize(t *testing.T) {
+	for _, size := range []int{8, 20, 32} {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		if result := c.SaltSize(); result != size {
+			t.Errorf("SaltSize result = %d; expected %d", result, size)
+		}
+		if result := c.Size(); result != sha1.Size+size {
+			t.Errorf("Size result = %d; expected %d", result, sha1.Size+size)
+		}
+	}
+}
+
+func TestSaltUnsafe(t *testing.T) {
+	c, err := NewWithSalt([]byte("n4pggXWL"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("supercalifragilisticexpialidocious"))
+	d := c.(*digest)
+
+	salt := d.SaltUnsafe()
+	if !bytes.Equal(salt, []byte("n4pggXWL")) {
+		t.Errorf("SaltUnsafe() = %q; expected %q", salt, "n4pggXWL")
+	}
+
+	// the returned slice aliases the digest, unlike that of Salt
+	before := c.Sum(nil)
+	c.(crypto.Salter).Salt()[0] = 'x'
+	if result := c.Sum(nil); !bytes.Equal(result, before) {
+		t.Errorf("modifying Salt() changed Sum: %x; expected %x", result, before)
+	}
+
+	salt[0] = 'x'
+	expected, err := Sum([]byte("supercalifragilisticexpialidocious"), []byte("x4pggXWL"))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum after modifying SaltUnsafe() = %x; expected %x", result, expected)
+	}
+}
+
+func TestSalt(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("Be yourself; everyone else is already taken."))
+	expected := c.Sum(nil)
+
+	salt := c.(crypto.Salter).Salt()
+	if len(salt) != DefaultNumSaltBytes {
+		t.Errorf("Salt length = %d; expected %d", len(salt), DefaultNumSaltBytes)
+	}
+	if !bytes.Equal(salt, expected[len(expected)-len(salt):]) {
+		t.Errorf("Salt result = %x; expected suffix of %x", salt, expected)
+	}
+
+	for i := range salt {
+		salt[i] ^= 0xff
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result after modifying Salt() = %x; expected %x", result, expected)
+	}
+}
+
+type saltPositionCase struct {
+	plaintext         []byte
+	salt              []byte
+	pos               SaltPosition
+	expectedHexString string
+}
+
+func TestSaltPosition(t *testing.T) {
+	cases := []saltPositionCase{
+		{[]byte("1234567890"), []byte("abcdefg"), SaltSuffix, "8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667"},
+		{[]byte("1234567890"), []byte("abcdefg"), SaltPrefix, "616263646566675d6705739fc0727a9e8939f6fb09692daa5c099e"},
+		{[]byte("protean-pith-anodyne-accolade-snare"), []byte("X"), SaltPrefix, "587450784da5e234ef53ef803ee6df85df512a572a"},
+		{[]byte("supercalifragilisticexpialidocious"), []byte("n4pggXWL"), SaltPrefix, "6e3470676758574cbbedc1b341897073cf6acda9be85c2592aec4f78"},
+	}
+
+	for _, c := range cases {
+		d, err := NewWithSaltPosition(c.salt, c.pos)
+		if err != nil {
+			t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+			continue
+		}
+		d.Write(c.plaintext)
+		if result := d.HexString(); result != c.expectedHexString {
+			t.Errorf("result = %s; expected %s", result, c.expectedHexString)
+		}
+
+		ssha1Hash, err := hex.DecodeString(c.expectedHexString)
+		if err != nil {
+			t.Errorf("unable to convert hex string '%s' to []byte.", err)
+		}
+		if result, err := ValidateWithSaltPosition(ssha1Hash, c.plaintext, c.pos); err != nil || !result {
+			t.Errorf("ValidateWithSaltPosition() = %t, %v; expected true for test case %v", result, err, c)
+		}
+
+		other := SaltPrefix
+		if c.pos == SaltPrefix {
+			other = SaltSuffix
+		}
+		if result, _ := ValidateWithSaltPosition(ssha1Hash, c.plaintext, other); result {
+			t.Errorf("ValidateWithSaltPosition() with wrong position succeeded for test case %v", c)
+		}
+	}
+
+	if _, err := NewWithSaltPosition([]byte("abcdefg"), SaltPosition(42)); err == nil {
+		t.Errorf("expected error for invalid salt position but none returned")
+	}
+	if _, err := NewWithSaltPosition([]byte{}, SaltPrefix); err == nil {
+		t.Errorf("expected error for empty salt but none returned")
+	}
+}
+
+func TestNewWithRand(t *testing.T) {
+	source := []byte("0123456789abcdefghijklmnopqrstuv")
+
+	c, err := NewWithRand(bytes.NewReader(source), 16)
+	if err != nil {
+		t.Errorf("method NewWithRand() returned unexpected error: %e", err)
+	}
+	if result := c.(crypto.Salter).Salt(); !bytes.Equal(result, source[:16]) {
+		t.Errorf("Salt result = %q; expected %q", result, source[:16])
+	}
+
+	c.Write([]byte("To be or not to be."))
+	expected, err := Sum([]byte("To be or not to be."), source[:16])
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result = %x; expected %x", result, expected)
+	}
+
+	if _, err := NewWithRand(bytes.NewReader(source), 0); err == nil {
+		t.Errorf("expected error for invalid salt size but none returned")
+	}
+	if _, err := NewWithRand(bytes.NewReader(source[:4]), 8); err == nil {
+		t.Errorf("expected error for short reader but none returned")
+	}
+	if _, err := NewWithRand(bytes.NewReader(make([]byte, 1024)), 16); !errors.Is(err, ErrWeakSalt) {
+		t.Errorf("NewWithRand() error = %v; expected %v", err, ErrWeakSalt)
+	}
+
+	// a weak salt is read again
+	weakThenGood := append(make([]byte, 16), source[:16]...)
+	c, err = NewWithRand(bytes.NewReader(weakThenGood), 16)
+	if err != nil {
+		t.Errorf("method NewWithRand() returned unexpected error: %e", err)
+	}
+	if result := c.(crypto.Salter).Salt(); !bytes.Equal(result, source[:16]) {
+		t.Errorf("Salt result = %q; expected %q", result, source[:16])
+	}
+}
+
+func TestNewWithConfig(t *testing.T) {
+	source := []byte("0123456789abcdefghijklmnopqrstuv")
+
+	c, err := NewWithConfig(Config{SaltSize: 12, Rand: bytes.NewReader(source)})
+	if err != nil {
+		t.Errorf("method NewWithConfig() returned unexpected error: %e", err)
+	}
+	if result := c.(crypto.Salter).Salt(); !bytes.Equal(result, source[:12]) {
+		t.Errorf("Salt result = %q; expected %q", result, source[:12])
+	}
+
+	// the default salt size applies when only the source is set
+	c, err = NewWithConfig(Config{Rand: bytes.NewReader(source)})
+	if err != nil {
+		t.Errorf("method NewWithConfig() returned unexpected error: %e", err)
+	}
+	if result := c.(crypto.Salter).Salt(); !bytes.Equal(result, source[:DefaultNumSaltBytes]) {
+		t.Errorf("Salt result = %q; expected %q", result, source[:DefaultNumSaltBytes])
+	}
+
+	// crypto/rand applies when only the salt size is set
+	for _, cfg := range []Config{{SaltSize: 32}, {}} {
+		c, err := NewWithConfig(cfg)
+		if err != nil {
+			t.Errorf("method NewWithConfig() returned unexpected error: %e", err)
+			continue
+		}
+		expected := cfg.SaltSize
+		if expected == 0 {
+			expected = DefaultNumSaltBytes
+		}
+		if result := c.SaltSize(); result != expected {
+			t.Errorf("SaltSize result = %d; expected %d", result, expected)
+		}
+	}
+
+	if _, err := NewWithConfig(Config{SaltSize: -1}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewWithConfig() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewWithConfig(Config{SaltSize: MaxSaltBytes + 1}); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewWithConfig() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+	if _, err := NewWithConfig(Config{SaltSize: 8, Rand: bytes.NewReader(source[:4])}); err == nil {
+		t.Errorf("expected error for short reader but none returned")
+	}
+}
+
+// weakThenReader returns salts of identical bytes for the first n reads,
+// then reads from r.
+type weakThenReader struct {
+	n     int
+	r     io.Reader
+	reads int
+}
+
+func (w *weakThenReader) Read(p []byte) (int, error) {
+	w.reads++
+	if w.reads <= w.n {
+		for i := range p {
+			p[i] = 'x'
+		}
+		return len(p), nil
+	}
+	return w.r.Read(p)
+}
+
+type optionsCase struct {
+	opts     Options
+	expected string
+}
+
+func TestNewWithOptions(t *testing.T) {
+	// the salt is chosen so that the encodings differ
+	salt := []byte{0xfb, 0xff, 0xbe, 0x3f, 0x3e, 0x7e, 0x01, 0x02}
+	plaintext := []byte("Keep it simple.")
+
+	cases := []optionsCase{
+		{Options{Salt: salt}, "{SSHA}TrTgmtrsla1ZKfQblULbHADTdkH7/74/Pn4BAg=="},
+		{Options{Salt: salt, Encoding: base64.URLEncoding}, "{SSHA}TrTgmtrsla1ZKfQblULbHADTdkH7_74_Pn4BAg=="},
+		{Options{Salt: salt, Encoding: base64.RawURLEncoding, Prefix: "{ssha}"}, "{ssha}TrTgmtrsla1ZKfQblULbHADTdkH7_74_Pn4BAg"},
+		{Options{Salt: salt, Encoding: base64.URLEncoding, NoPrefix: true}, "TrTgmtrsla1ZKfQblULbHADTdkH7_74_Pn4BAg=="},
+		// the salt takes precedence over SaltSize and Rand
+		{Options{Salt: salt, SaltSize: 4, Rand: bytes.NewReader(nil)}, "{SSHA}TrTgmtrsla1ZKfQblULbHADTdkH7/74/Pn4BAg=="},
+		// a salt read from Rand
+		{Options{SaltSize: len(salt), Rand: bytes.NewReader(salt), Encoding: base64.URLEncoding}, "{SSHA}TrTgmtrsla1ZKfQblULbHADTdkH7_74_Pn4BAg=="},
+	}
+
+	for _, c := range cases {
+		h, err := NewWithOptions(c.opts)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+		h.Write(plaintext)
+
+		if result := h.String(); result != c.expected {
+			t.Errorf("String result = %s; expected %s", result, c.expected)
+		}
+		if result := string(h.(*digest).AppendString(nil)); result != c.expected {
+			t.Errorf("AppendString result = %s; expected %s", result, c.expected)
+		}
+		if result := h.(crypto.Cloner).Clone().String(); result != c.expected {
+			t.Errorf("String result of Clone = %s; expected %s", result, c.expected)
+		}
+	}
+
+	// random salts of the default size
+	h, err := NewWithOptions(Options{})
+	if err != nil {
+		t.Errorf("method NewWithOptions() returned unexpected error: %e", err)
+	} else if h.SaltSize() != DefaultNumSaltBytes {
+		t.Errorf("SaltSize result = %d; expected %d", h.SaltSize(), DefaultNumSaltBytes)
+	}
+
+	if _, err := NewWithOptions(Options{Salt: []byte{}}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewWithOptions() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewWithOptions(Options{SaltSize: MaxSaltBytes + 1}); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewWithOptions() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+}
+
+func TestNewWithConfigSaltAttempts(t *testing.T) {
+	source := []byte("0123456789abcdef")
+
+	// the budget covers the weak reads
+	r := &weakThenReader{n: 2, r: bytes.NewReader(source)}
+	c, err := NewWithConfig(Config{SaltSize: 8, Rand: r, SaltAttempts: 3})
+	if err != nil {
+		t.Errorf("method NewWithConfig() returned unexpected error: %e", err)
+	} else if result := c.(crypto.Salter).Salt(); !bytes.Equal(result, source[:8]) {
+		t.Errorf("Salt result = %q; expected %q", result, source[:8])
+	}
+	if r.reads != 3 {
+		t.Errorf("NewWithConfig() made %d reads; expected 3", r.reads)
+	}
+
+	// the budget is exhausted
+	r = &weakThenReader{n: 3, r: bytes.NewReader(source)}
+	if _, err := NewWithConfig(Config{SaltSize: 8, Rand: r, SaltAttempts: 3}); !errors.Is(err, ErrWeakSalt) {
+		t.Errorf("NewWithConfig() error = %v; expected %v", err, ErrWeakSalt)
+	}
+	if r.reads != 3 {
+		t.Errorf("NewWithConfig() made %d reads; expected 3", r.reads)
+	}
+
+	// the default budget is larger than that
+	r = &weakThenReader{n: 3, r: bytes.NewReader(source)}
+	if _, err := NewWithConfig(Config{SaltSize: 8, Rand: r}); err != nil {
+		t.Errorf("method NewWithConfig() returned unexpected error: %e", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewWithSalt(nil); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("NewWithSalt(nil) error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := NewWithSalt([]byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewWithSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewWithSaltPosition(nil, SaltPrefix); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("NewWithSaltPosition(nil) error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := NewWithSaltPosition([]byte{}, SaltPrefix); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewWithSaltPosition() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewForSaltSize(0); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewForSaltSize() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := Validate(make([]byte, sha1.Size-1), nil); !errors.Is(err, ErrSliceTooShortSHA1) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSliceTooShortSHA1)
+	}
+	if _, err := Validate(make([]byte, sha1.Size), nil); !errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSliceTooShortSSHA1)
+	}
+}
+
+func TestValidateStringErrors(t *testing.T) {
+	if _, err := ValidateString("{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil); !errors.Is(err, ErrMalformedPrefix) {
+		t.Errorf("ValidateString() error = %v; expected %v", err, ErrMalformedPrefix)
+	}
+	if _, err := ValidateString("{SSHA}not*valid*base64!", nil); !errors.Is(err, ErrInvalidBase64) {
+		t.Errorf("ValidateString() error = %v; expected %v", err, ErrInvalidBase64)
+	}
+	if _, err := NewWithSaltPosition([]byte("abcdefg"), SaltPosition(42)); !errors.Is(err, ErrInvalidSaltPosition) {
+		t.Errorf("NewWithSaltPosition() error = %v; expected %v", err, ErrInvalidSaltPosition)
+	}
+}
+
+func TestMaxSaltBytes(t *testing.T) {
+	if _, err := NewWithSalt(make([]byte, MaxSaltBytes)); err != nil {
+		t.Errorf("NewWithSalt() returned unexpected error for %d-byte salt: %e", MaxSaltBytes, err)
+	}
+	if _, err := NewWithSalt(make([]byte, MaxSaltBytes+1)); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewWithSalt() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+	if _, err := NewForSaltSize(MaxSaltBytes); err != nil {
+		t.Errorf("NewForSaltSize() returned unexpected error for %d-byte salt: %e", MaxSaltBytes, err)
+	}
+	if _, err := NewForSaltSize(MaxSaltBytes + 1); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewForSaltSize() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+
+	sample := []byte("Well done is better than well said.")
+	stored, err := Sum(sample, make([]byte, MaxSaltBytes))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result, err := Validate(stored, sample); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+	if _, err := Validate(make([]byte, sha1.Size+MaxSaltBytes+1), sample); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+
+	// an implausibly large blob is rejected by length, even if it looks
+	// like an encoded hash
+	huge := "{SSHA}" + base64.StdEncoding.EncodeToString(make([]byte, 4*MaxSaltBytes))
+	if _, err := Validate([]byte(huge), sample); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+	if _, err := ValidateReader([]byte(huge), strings.NewReader(string(sample))); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("ValidateReader() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+
+	// ... whereas an encoded hash of the largest salt is still detected
+	encoded := "{SSHA}" + base64.StdEncoding.EncodeToString(stored)
+	if _, err := Validate([]byte(encoded), sample); !errors.Is(err, ErrEncodedHash) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrEncodedHash)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	salt := []byte("q8Vn2Rws")
+	first := []byte("It does not matter how slowly you go ")
+	second := []byte("as long as you do not stop.")
+
+	for _, pos := range []SaltPosition{SaltSuffix, SaltPrefix} {
+		c, err := NewWithSaltPosition(salt, pos)
+		if err != nil {
+			t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+			continue
+		}
+		c.Write(first)
+
+		state, err := c.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Errorf("method MarshalBinary() returned unexpected error: %e", err)
+			continue
+		}
+
+		restored, err := New()
+		if err != nil {
+			t.Errorf("method New() returned unexpected error: %e", err)
+			continue
+		}
+		if err := restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Errorf("method UnmarshalBinary() returned unexpected error: %e", err)
+			continue
+		}
+
+		c.Write(second)
+		restored.Write(second)
+
+		if !bytes.Equal(restored.(crypto.Salter).Salt(), salt) {
+			t.Errorf("restored Salt = %q; expected %q", restored.(crypto.Salter).Salt(), salt)
+		}
+		if result, expected := restored.Sum(nil), c.Sum(nil); !bytes.Equal(result, expected) {
+			t.Errorf("restored Sum result = %x; expected %x", result, expected)
+		}
+	}
+}
+
+func TestMarshalBinaryResume(t *testing.T) {
+	salt := []byte("q8Vn2Rws")
+	data := bytes.Repeat([]byte("It does not matter how slowly you go. "), 6)
+
+	for _, pos := range []SaltPosition{SaltSuffix, SaltPrefix} {
+		uninterrupted, err := NewWithSaltPosition(salt, pos)
+		if err != nil {
+			t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+			continue
+		}
+		uninterrupted.Write(data)
+		expected := uninterrupted.Sum(nil)
+
+		// split points on either side of the SHA-1 block size, so that the
+		// state is captured with and without buffered bytes
+		for _, split := range []int{0, 1, BlockSize - 1, BlockSize, BlockSize + 1, len(data) - 1, len(data)} {
+			c, err := NewWithSaltPosition(salt, pos)
+			if err != nil {
+				t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+				continue
+			}
+			c.Write(data[:split])
+
+			state, err := c.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Errorf("method MarshalBinary() returned unexpected error: %e", err)
+				continue
+			}
+
+			restored, err := New()
+			if err != nil {
+				t.Errorf("method New() returned unexpected error: %e", err)
+				continue
+			}
+			if err := restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Errorf("method UnmarshalBinary() returned unexpected error: %e", err)
+				continue
+			}
+
+			restored.Write(data[split:])
+			if result := restored.Sum(nil); !bytes.Equal(result, expected) {
+				t.Errorf("restored Sum result (pos %d, split %d) = %x; expected %x", pos, split, result, expected)
+			}
+
+			// marshaling leaves the original usable
+			c.Write(data[split:])
+			if result := c.Sum(nil); !bytes.Equal(result, expected) {
+				t.Errorf("original Sum result (pos %d, split %d) = %x; expected %x", pos, split, result, expected)
+			}
+		}
+	}
+}
+
+func TestUnmarshalBinaryWritten(t *testing.T) {
+	c, err := NewWithSalt([]byte("q8Vn2Rws"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("12345678"))
+	state, err := c.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Errorf("method MarshalBinary() returned unexpected error: %e", err)
+	}
+
+	// the byte count is that of the state, not of the receiver's past
+	restored, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	restored.Write(make([]byte, 20))
+	if err := restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		t.Errorf("method UnmarshalBinary() returned unexpected error: %e", err)
+	}
+	restored.(*digest).SetMaxInput(10)
+	if n, err := restored.Write([]byte("90a")); !errors.Is(err, ErrInputTooLarge) || n != 2 {
+		t.Errorf("restored Write() = %d, %v; expected 2, %v", n, err, ErrInputTooLarge)
+	}
+
+	// a limit below the restored byte count rejects further writes
+	c.Write([]byte("90abcdef"))
+	state, err = c.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Errorf("method MarshalBinary() returned unexpected error: %e", err)
+	}
+	limited, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	limited.(*digest).SetMaxInput(4)
+	if err := limited.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		t.Errorf("method UnmarshalBinary() returned unexpected error: %e", err)
+	}
+	if n, err := limited.(io.StringWriter).WriteString("x"); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("limited WriteString() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+	if result, expected := limited.Sum(nil), c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("limited Sum result = %x; expected %x", result, expected)
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	c, err := NewWithSalt([]byte("q8Vn2Rws"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	state, err := c.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Errorf("method MarshalBinary() returned unexpected error: %e", err)
+	}
+
+	badMagic := append([]byte("xxxxx"), state[5:]...)
+	badVersion := append([]byte(nil), state...)
+	badVersion[5] = 0xff
+	badPos := append([]byte(nil), state...)
+	badPos[6] = 0xff
+	badWritten := append([]byte(nil), state...)
+	badWritten[9] = 0x80
+	truncated := state[:len(state)-1]
+
+	cases := []struct {
+		state    []byte
+		expected error
+	}{
+		{nil, ErrInvalidState},
+		{badMagic, ErrInvalidState},
+		{badVersion, ErrUnsupportedStateVersion},
+		{badPos, ErrInvalidSaltPosition},
+		{badWritten, ErrInvalidState},
+		{truncated, ErrInvalidState},
+	}
+
+	for _, tc := range cases {
+		if err := c.(encoding.BinaryUnmarshaler).UnmarshalBinary(tc.state); !errors.Is(err, tc.expected) {
+			t.Errorf("UnmarshalBinary() error = %v; expected %v", err, tc.expected)
+		}
+	}
+}
+
+func TestURLString(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("You have to be odd to be number one."))
+
+	// the sum contains bytes that encode to '+' and '/' in the standard alphabet
+	expectedStd := "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="
+	expectedURL := "{SSHA}h-WWKpgLY_OQorn-uHAi7Gsr9LZSKncuNVZtbw=="
+
+	if result := c.String(); result != expectedStd {
+		t.Errorf("String result = %s; expected %s", result, expectedStd)
+	}
+	if result := c.(crypto.URLStringer).URLString(); result != expectedURL {
+		t.Errorf("URLString result = %s; expected %s", result, expectedURL)
+	}
+}
+
+func TestValidateAny(t *testing.T) {
+	// salt: "R*w.5Vmo"
+	encoded := "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="
+
+	if result, err := crypto.ValidateAny(encoded, []byte("You have to be odd to be number one.")); err != nil || !result {
+		t.Errorf("ValidateAny() = %t, %v; expected true, nil", result, err)
+	}
+	if result, err := crypto.ValidateAny(encoded, []byte("You have to be odd to be number two.")); err != nil || result {
+		t.Errorf("ValidateAny() = %t, %v; expected false, nil", result, err)
+	}
+}
+
+func TestWriteString(t *testing.T) {
+	salt := []byte("c0Ffee42")
+
+	c1, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c2, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c1.(io.StringWriter).WriteString("abc")
+	c2.Write([]byte("abc"))
+
+	if result, expected := c1.Sum(nil), c2.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("WriteString Sum result = %x; expected %x", result, expected)
+	}
+}
+
+func TestClone(t *testing.T) {
+	salt := []byte("fK2o9WbZ")
+	prefix := []byte("Common prefix, ")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write(prefix)
+
+	clone := c.(crypto.Cloner).Clone()
+	c.Write([]byte("first suffix"))
+	clone.Write([]byte("second suffix"))
+
+	expected, err := Sum(append(append([]byte{}, prefix...), "first suffix"...), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("original Sum result = %x; expected %x", result, expected)
+	}
+
+	expected, err = Sum(append(append([]byte{}, prefix...), "second suffix"...), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := clone.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("clone Sum result = %x; expected %x", result, expected)
+	}
+}
+
+var benchInputs = []struct {
+	name string
+	data []byte
+}{
+	{"password", []byte("hunter2!")},
+	{"token", bytes.Repeat([]byte("eyJhbGciOiJIUzI1NiJ9"), 50)},
+}
+
+var benchSaltSizes = []int{8, 20, 32}
+
+func BenchmarkSum(b *testing.B) {
+	for _, in := range benchInputs {
+		for _, size := range benchSaltSizes {
+			salt := bytes.Repeat([]byte{'s'}, size)
+			b.Run(fmt.Sprintf("%s/salt%d", in.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(in.data)))
+				for i := 0; i < b.N; i++ {
+					Sum(in.data, salt)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	for _, in := range benchInputs {
+		for _, size := range benchSaltSizes {
+			stored, err := Sum(in.data, bytes.Repeat([]byte{'s'}, size))
+			if err != nil {
+				b.Fatalf("method Sum() returned unexpected error: %e", err)
+			}
+			b.Run(fmt.Sprintf("%s/salt%d", in.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(in.data)))
+				for i := 0; i < b.N; i++ {
+					Validate(stored, in.data)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	for _, size := range benchSaltSizes {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			b.Fatalf("method NewForSaltSize() returned unexpected error: %e", err)
+		}
+		c.Write(benchInputs[0].data)
+		b.Run(fmt.Sprintf("salt%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = c.String()
+			}
+		})
+	}
+}
+
+func BenchmarkAppendString(b *testing.B) {
+	for _, size := range benchSaltSizes {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			b.Fatalf("method NewForSaltSize() returned unexpected error: %e", err)
+		}
+		c.Write(benchInputs[0].data)
+		d := c.(*digest)
+		b.Run(fmt.Sprintf("String/salt%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = []byte(d.String())
+			}
+		})
+		b.Run(fmt.Sprintf("AppendString/salt%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf = d.AppendString(buf[:0])
+			}
+		})
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 8, 20, MaxSaltBytes} {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+		}
+		c.Write([]byte("Who you are authentically is alright."))
+		d := c.(*digest)
+
+		expected := d.String()
+		if result := string(d.AppendString(nil)); result != expected {
+			t.Errorf("AppendString(nil) = %s; expected %s", result, expected)
+		}
+
+		// appends after existing content, with and without spare capacity
+		for _, dst := range [][]byte{[]byte("hash="), append(make([]byte, 0, 4096), "hash="...)} {
+			result := string(d.AppendString(dst))
+			if result != "hash="+expected {
+				t.Errorf("AppendString(%q) = %s; expected %s", dst, result, "hash="+expected)
+			}
+		}
+	}
+}
+
+func TestSetMaxInput(t *testing.T) {
+	salt := []byte("R*w.5Vmo")
+	data := []byte("You have to be odd to be number one.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	d := c.(*digest)
+
+	// unlimited by default
+	if n, err := d.Write(data); err != nil || n != len(data) {
+		t.Errorf("Write() = %d, %v; expected %d, nil", n, err, len(data))
+	}
+
+	d.Reset()
+	d.SetMaxInput(10)
+	if n, err := d.Write(data[:6]); err != nil || n != 6 {
+		t.Errorf("Write() = %d, %v; expected 6, nil", n, err)
+	}
+	if n, err := d.WriteString(string(data[6:10])); err != nil || n != 4 {
+		t.Errorf("WriteString() = %d, %v; expected 4, nil", n, err)
+	}
+	if n, err := d.Write(data[10:]); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("Write() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+	expected, err := Sum(data[:10], salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := d.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result = %x; expected the sum of the first 10 bytes %x", result, expected)
+	}
+
+	// a write straddling the limit is cut short
+	d.Reset()
+	if n, err := d.Write(data); !errors.Is(err, ErrInputTooLarge) || n != 10 {
+		t.Errorf("Write() = %d, %v; expected 10, %v", n, err, ErrInputTooLarge)
+	}
+	if n, err := d.WriteString("x"); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("WriteString() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+
+	// the limit carries over to clones
+	d.Reset()
+	d.Write(data[:8])
+	clone := d.Clone()
+	if n, err := clone.Write(data[8:]); !errors.Is(err, ErrInputTooLarge) || n != 2 {
+		t.Errorf("clone Write() = %d, %v; expected 2, %v", n, err, ErrInputTooLarge)
+	}
+
+	// copying stops at the limit
+	if _, err := io.Copy(d, strings.NewReader(string(data))); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("io.Copy() error = %v; expected %v", err, ErrInputTooLarge)
+	}
+
+	d.SetMaxInput(0)
+	if n, err := d.Write(data); err != nil || n != len(data) {
+		t.Errorf("Write() after removing the limit = %d, %v; expected %d, nil", n, err, len(data))
+	}
+}
+
+func TestSetMaxInputReached(t *testing.T) {
+	salt := []byte("R*w.5Vmo")
+	data := []byte("You have to be odd to be number one.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	d := c.(*digest)
+
+	// limit already reached
+	d.SetMaxInput(16)
+	if n, err := d.Write(data[:16]); err != nil || n != 16 {
+		t.Errorf("Write() = %d, %v; expected 16, nil", n, err)
+	}
+	if n, err := d.Write([]byte("x")); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("Write() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+	if n, err := d.WriteString("x"); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("WriteString() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+
+	// SetMaxInput below the bytes already written
+	d.SetMaxInput(4)
+	if n, err := d.Write([]byte("x")); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("Write() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+	if n, err := d.WriteString("x"); !errors.Is(err, ErrInputTooLarge) || n != 0 {
+		t.Errorf("WriteString() = %d, %v; expected 0, %v", n, err, ErrInputTooLarge)
+	}
+	if n, err := d.Write(nil); err != nil || n != 0 {
+		t.Errorf("Write(nil) = %d, %v; expected 0, nil", n, err)
+	}
+
+	expected, err := Sum(data[:16], salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := d.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result = %x; expected the sum of the first 16 bytes %x", result, expected)
+	}
+}
+
+// failingWriter accepts up to n bytes, then fails.
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, io.ErrShortWrite
+	}
+	return len(p), nil
+}
+
+func TestWriteTo(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("You have to be odd to be number one."))
+
+	var buf bytes.Buffer
+	n, err := c.(io.WriterTo).WriteTo(&buf)
+	if err != nil {
+		t.Errorf("method WriteTo() returned unexpected error: %e", err)
+	}
+	if expected := c.Sum(nil); !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("WriteTo wrote %x; expected %x", buf.Bytes(), expected)
+	}
+	if n != int64(c.Size()) {
+		t.Errorf("WriteTo() = %d; expected %d", n, c.Size())
+	}
+
+	n, err = c.(io.WriterTo).WriteTo(&failingWriter{n: 5})
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("WriteTo() error = %v; expected %v", err, io.ErrShortWrite)
+	}
+	if n != 5 {
+		t.Errorf("WriteTo() = %d; expected 5", n)
+	}
+}
+
+func TestStringReader(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("You have to be odd to be number one."))
+	d := c.(*digest)
+
+	r := d.StringReader()
+	d.Write([]byte(" Or not.")) // does not affect r
+
+	result, err := io.ReadAll(r)
+	if err != nil {
+		t.Errorf("method ReadAll() returned unexpected error: %e", err)
+	}
+	expected := "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="
+	if string(result) != expected {
+		t.Errorf("StringReader() read %s; expected %s", result, expected)
+	}
+
+	if result, err := io.ReadAll(d.StringReader()); err != nil || string(result) != d.String() {
+		t.Errorf("StringReader() read %s, %v; expected %s", result, err, d.String())
+	}
+}
+
+type verifyCase struct {
+	stored   string
+	password []byte
+	expected bool
+}
+
+func TestVerify(t *testing.T) {
+	cases := []verifyCase{
+		// salt: "R*w.5Vmo"
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), true},
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), true},
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be even to be number one."), false},
+		// salt: "abcdefg"
+		{"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", []byte("1234567890"), true},
+		{"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", []byte("123456789"), false},
+		// salt: "X"
+		{"aRvqrBMKC+JdxRfeTmORM009DzdY", []byte("protean-pith-anodyne-accolade-snare"), true},
+		// salt: "ajE94aZM"
+		{"{SSHA}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==", []byte("When life gives you lemons, make lemonade."), true},
+	}
+
+	for _, c := range cases {
+		result, err := Verify(c.stored, c.password)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("verification test failed for test case %v", c)
+		}
+	}
+
+	if _, err := Verify("{SSHA}AAAA", nil); err == nil {
+		t.Errorf("expected error for too-short stored hash but none returned")
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	password := []byte("Who you are authentically is alright.")
+
+	stored, err := HashPassword(password)
+	if err != nil {
+		t.Errorf("method HashPassword() returned unexpected error: %e", err)
+	}
+	if !strings.HasPrefix(stored, scheme) {
+		t.Errorf("HashPassword() = %s; expected prefix %s", stored, scheme)
+	}
+	if size, err := SaltSizeOf(stored); err != nil || size != DefaultNumSaltBytes {
+		t.Errorf("SaltSizeOf(HashPassword()) = %d, %v; expected %d", size, err, DefaultNumSaltBytes)
+	}
+
+	ok, err := Verify(stored, password)
+	if err != nil {
+		t.Errorf("method Verify() returned unexpected error: %e", err)
+	}
+	if !ok {
+		t.Errorf("Verify() failed for the output of HashPassword()")
+	}
+
+	ok, err = Verify(stored, []byte("Who you are authentically is alright"))
+	if err != nil {
+		t.Errorf("method Verify() returned unexpected error: %e", err)
+	}
+	if ok {
+		t.Errorf("Verify() succeeded for a wrong password")
+	}
+
+	// a fresh salt is used each time
+	again, err := HashPassword(password)
+	if err != nil {
+		t.Errorf("method HashPassword() returned unexpected error: %e", err)
+	}
+	if again == stored {
+		t.Errorf("HashPassword() returned %s twice", stored)
+	}
+}
+
+func TestSumWithGeneratedSalt(t *testing.T) {
+	data := []byte("Whatever you are, be a good one.")
+
+	sum, salt, err := SumWithGeneratedSalt(data, 12)
+	if err != nil {
+		t.Errorf("method SumWithGeneratedSalt() returned unexpected error: %e", err)
+	}
+	if len(salt) != 12 {
+		t.Errorf("salt length = %d; expected %d", len(salt), 12)
+	}
+	if !bytes.Equal(sum[sha1.Size:], salt) {
+		t.Errorf("sum %x does not end with salt %x", sum, salt)
+	}
+
+	expected, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(sum, expected) {
+		t.Errorf("SumWithGeneratedSalt result = %x; expected %x", sum, expected)
+	}
+	if result, err := Validate(sum, data); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+
+	if _, _, err := SumWithGeneratedSalt(data, 0); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumWithGeneratedSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
+
+func TestSumEach(t *testing.T) {
+	salt := []byte("n4pggXWL")
+	messages := [][]byte{
+		[]byte("supercalifragilisticexpialidocious"),
+		{},
+		[]byte("abcdefghijklmnopqrstuvwxyz"),
+		bytes.Repeat([]byte("eyJhbGciOiJIUzI1NiJ9"), 50),
+		[]byte("supercalifragilisticexpialidocious"),
+	}
+
+	sums, err := SumEach(messages, salt)
+	if err != nil {
+		t.Errorf("method SumEach() returned unexpected error: %e", err)
+	}
+	if len(sums) != len(messages) {
+		t.Fatalf("len(SumEach()) = %d; expected %d", len(sums), len(messages))
+	}
+	for i, m := range messages {
+		expected, err := Sum(m, salt)
+		if err != nil {
+			t.Errorf("method Sum() returned unexpected error: %e", err)
+		}
+		if !bytes.Equal(sums[i], expected) {
+			t.Errorf("SumEach()[%d] = %x; expected %x", i, sums[i], expected)
+		}
+	}
+	if hex.EncodeToString(sums[0]) != "8eadde532169b6908034886be119c9f0ca61801e6e3470676758574c" {
+		t.Errorf("SumEach()[0] = %x; expected the known vector", sums[0])
+	}
+
+	// appending to one sum does not clobber the next
+	_ = append(sums[0], 'x')
+	if expected, _ := Sum(messages[1], salt); !bytes.Equal(sums[1], expected) {
+		t.Errorf("SumEach()[1] = %x after append; expected %x", sums[1], expected)
+	}
+
+	if sums, err := SumEach(nil, salt); err != nil || len(sums) != 0 {
+		t.Errorf("SumEach(nil) = %v, %v; expected no sums", sums, err)
+	}
+	if _, err := SumEach(messages, nil); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("SumEach() error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := SumEach(messages, []byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumEach() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := SumEach(messages, make([]byte, MaxSaltBytes+1)); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("SumEach() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+}
+
+func TestSumString(t *testing.T) {
+	for _, c := range []sumCase{
+		{[]byte("supercalifragilisticexpialidocious"), []byte("n4pggXWL"), ""},
+		{[]byte("You have to be odd to be number one."), []byte("R*w.5Vmo"), ""},
+		{[]byte{}, []byte("X"), ""},
+	} {
+		result, err := SumString(c.plaintext, c.salt)
+		if err != nil {
+			t.Errorf("method SumString() returned unexpected error: %e", err)
+		}
+
+		h, err := NewWithSalt(c.salt)
+		if err != nil {
+			t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+		}
+		h.Write(c.plaintext)
+		if expected := h.String(); result != expected {
+			t.Errorf("SumString() = %s; expected %s", result, expected)
+		}
+	}
+
+	// a random salt of the default size for a nil salt
+	result, err := SumString([]byte("data"), nil)
+	if err != nil {
+		t.Errorf("method SumString() returned unexpected error: %e", err)
+	}
+	if size, err := SaltSizeOf(result); err != nil || size != DefaultNumSaltBytes {
+		t.Errorf("SaltSizeOf(SumString()) = %d, %v; expected %d", size, err, DefaultNumSaltBytes)
+	}
+	if ok, err := ValidateString(result, []byte("data")); err != nil || !ok {
+		t.Errorf("ValidateString(SumString()) = %t, %v; expected true, nil", ok, err)
+	}
+
+	if _, err := SumString([]byte("data"), []byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumString() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
+
+type trailingSaltCase struct {
+	message []byte
+	salt    []byte
+}
+
+func TestSumTrailingSalt(t *testing.T) {
+	trailingSaltCases := []trailingSaltCase{
+		{[]byte("supercalifragilisticexpialidocious"), []byte("n4pggXWL")},
+		{[]byte("abcdefghijklmnopqrstuvwxyz"), []byte("K")},
+		{[]byte{}, []byte("salt only")},
+	}
+
+	for _, c := range trailingSaltCases {
+		data := append(append([]byte(nil), c.message...), c.salt...)
+		result, err := SumTrailingSalt(data, len(c.salt))
+		if err != nil {
+			t.Errorf("method SumTrailingSalt() returned unexpected error: %e", err)
+		}
+
+		h, err := NewWithSalt(c.salt)
+		if err != nil {
+			t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+		}
+		h.Write(c.message)
+		expected := h.Sum(nil)
+
+		if !bytes.Equal(result, expected) {
+			t.Errorf("SumTrailingSalt(%q, %d) = %x; expected %x", data, len(c.salt), result, expected)
+		}
+	}
+
+	if _, err := SumTrailingSalt([]byte("data"), 0); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumTrailingSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := SumTrailingSalt([]byte("data"), -1); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumTrailingSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := SumTrailingSalt([]byte("data"), 5); !errors.Is(err, ErrSliceTooShortSalt) {
+		t.Errorf("SumTrailingSalt() error = %v; expected %v", err, ErrSliceTooShortSalt)
+	}
+}
+
+func TestSumReader(t *testing.T) {
+	salt := []byte("rE4d3rXy")
+	data := strings.Repeat("All that glitters is not gold. ", 4096)
+
+	expected, err := Sum([]byte(data), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+
+	for _, r := range []io.Reader{strings.NewReader(data), bytes.NewReader([]byte(data))} {
+		result, err := SumReader(r, salt)
+		if err != nil {
+			t.Errorf("method SumReader() returned unexpected error: %e", err)
+		}
+		if !bytes.Equal(result, expected) {
+			t.Errorf("SumReader result = %x; expected %x", result, expected)
+		}
+	}
+
+	readErr := errors.New("read failed")
+	if _, err := SumReader(iotest.ErrReader(readErr), salt); !errors.Is(err, readErr) {
+		t.Errorf("SumReader() error = %v; expected %v", err, readErr)
+	}
+	if _, err := SumReader(strings.NewReader(data), []byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumReader() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
+
+// cancelingReader returns chunks of data and cancels its context once the
+// limit of reads has been reached.
+type cancelingReader struct {
+	chunk  []byte
+	reads  int
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.reads == r.limit {
+		r.cancel()
+	}
+	return copy(p, r.chunk), nil
+}
+
+func TestSumReaderContext(t *testing.T) {
+	salt := []byte("rE4d3rXy")
+	data := strings.Repeat("All that glitters is not gold. ", 4096)
+
+	expected, err := Sum([]byte(data), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+
+	result, err := SumReaderContext(context.Background(), iotest.OneByteReader(strings.NewReader(data)), salt)
+	if err != nil {
+		t.Errorf("method SumReaderContext() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(result, expected) {
+		t.Errorf("SumReaderContext result = %x; expected %x", result, expected)
+	}
+
+	// the reader never ends, so only the cancellation stops the loop
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := &cancelingReader{chunk: []byte(data[:1024]), limit: 3, cancel: cancel}
+	if _, err := SumReaderContext(ctx, r, salt); !errors.Is(err, context.Canceled) {
+		t.Errorf("SumReaderContext() error = %v; expected %v", err, context.Canceled)
+	}
+	if r.reads != r.limit {
+		t.Errorf("SumReaderContext() read %d times after cancellation; expected %d", r.reads, r.limit)
+	}
+
+	readErr := errors.New("read failed")
+	if _, err := SumReaderContext(context.Background(), iotest.ErrReader(readErr), salt); !errors.Is(err, readErr) {
+		t.Errorf("SumReaderContext() error = %v; expected %v", err, readErr)
+	}
+	if _, err := SumReaderContext(context.Background(), strings.NewReader(data), []byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("SumReaderContext() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
+
+func TestValidateSaltUpperBound(t *testing.T) {
+	sample := []byte("Brevity is the soul of wit.")
+
+	normal, err := Sum(sample, []byte("8bYtes!!"))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result, err := Validate(normal, sample); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+
+	atLimit, err := Sum(sample, bytes.Repeat([]byte{'s'}, MaxSaltBytes))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result, err := Validate(atLimit, sample); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+
+	for _, size := range []int{MaxSaltBytes + 1, 10 << 20} {
+		blob := make([]byte, sha1.Size+size)
+		if result, err := Validate(blob, sample); !errors.Is(err, ErrSaltTooLong) || result {
+			t.Errorf("Validate() of %d-byte salt = %t, %v; expected false, %v", size, result, err, ErrSaltTooLong)
+		}
+	}
+}
+
+type prefixCase struct {
+	prefix   string
+	expected string
+}
+
+func TestStringWithPrefix(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("You have to be odd to be number one."))
+
+	cases := []prefixCase{
+		{"{ssha}", "{ssha}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="},
+		{"SSHA:", "SSHA:h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="},
+		{"", "h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="},
+	}
+
+	for _, tc := range cases {
+		if result := c.(crypto.PrefixStringer).StringWithPrefix(tc.prefix); result != tc.expected {
+			t.Errorf("StringWithPrefix(%q) result = %s; expected %s", tc.prefix, result, tc.expected)
+		}
+	}
+	if result := c.(crypto.PrefixStringer).StringWithPrefix("{SSHA}"); result != c.String() {
+		t.Errorf("StringWithPrefix(%q) result = %s; expected String() result %s", "{SSHA}", result, c.String())
+	}
+}
+
+func FuzzValidate(f *testing.F) {
+	seeds := []string{
+		"8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667",
+		"691beaac130a0be25dc517de4e6391334d3d0f3758",
+		"520d41b29f891bbaccf31d",
+		"9ab50f27d4201db9b28483ba83c48ebafbb2aa17",
+		"",
+	}
+	for _, seed := range seeds {
+		ssha1Hash, err := hex.DecodeString(seed)
+		if err != nil {
+			f.Fatalf("unable to convert hex string '%s' to []byte.", err)
+		}
+		f.Add(ssha1Hash, []byte("1234567890"))
+	}
+
+	f.Fuzz(func(t *testing.T, ssha1Hash, sample []byte) {
+		for _, pos := range []SaltPosition{SaltSuffix, SaltPrefix} {
+			result, err := ValidateWithSaltPosition(ssha1Hash, sample, pos)
+			if err != nil && result {
+				t.Errorf("ValidateWithSaltPosition() returned true along with error: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkSumTo(b *testing.B) {
+	c, err := NewWithSalt([]byte("8bYtes!!"))
+	if err != nil {
+		b.Fatalf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write(benchInputs[0].data)
+
+	b.Run("Sum", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = c.Sum(nil)
+		}
+	})
+	b.Run("SumTo", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, c.Size())
+		for i := 0; i < b.N; i++ {
+			buf, _ = crypto.SumTo(c, buf)
+		}
+	})
+}
+
+type decodeCase struct {
+	ssha1HashString string
+	expectedSalt    []byte
+	expectedErr     error
+}
+
+func TestDecode(t *testing.T) {
+	cases := []decodeCase{
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667", []byte("abcdefg"), nil},
+		{"f14713de1964843beae542b4f13024398549ac7d783579756e66435d3372726a772a40566542784e65572a6f52702d504d3e732a", []byte("x5yunfC]3rrjw*@VeBxNeW*oRp-PM>s*"), nil},
+		{"691beaac130a0be25dc517de4e6391334d3d0f3758", []byte("X"), nil},
+		{"520d41b29f891bbaccf31d", nil, ErrSliceTooShortSHA1},
+		{"9ab50f27d4201db9b28483ba83c48ebafbb2aa17", nil, ErrSliceTooShortSSHA1},
+	}
+
+	for _, c := range cases {
+		ssha1Hash, err := hex.DecodeString(c.ssha1HashString)
+		if err != nil {
+			t.Errorf("unable to convert hex string '%s' to []byte.", err)
+		}
+
+		sha1Part, salt, err := Decode(ssha1Hash)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("Decode() error = %v; expected %v", err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+		if !bytes.Equal(salt, c.expectedSalt) {
+			t.Errorf("Decode salt = %q; expected %q", salt, c.expectedSalt)
+		}
+		if !bytes.Equal(sha1Part, ssha1Hash[:sha1.Size]) {
+			t.Errorf("Decode SHA-1 part = %x; expected %x", sha1Part, ssha1Hash[:sha1.Size])
+		}
+	}
+}
+
+func TestDecodeErrorLengths(t *testing.T) {
+	cases := []struct {
+		ssha1Hash []byte
+		expected  error
+		message   string
+	}{
+		{make([]byte, 11), ErrSliceTooShortSHA1, "got 11, need >= 20"},
+		{nil, ErrSliceTooShortSHA1, "got 0, need >= 20"},
+		{make([]byte, 20), ErrSliceTooShortSSHA1, "got 20, need >= 21"},
+	}
+
+	for _, c := range cases {
+		_, _, err := Decode(c.ssha1Hash)
+		if !errors.Is(err, c.expected) {
+			t.Errorf("Decode() error = %v; expected %v", err, c.expected)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.message) {
+			t.Errorf("Decode() error = %q; expected it to contain %q", err, c.message)
+		}
+	}
+}
+
+func TestDecodeBareDigest(t *testing.T) {
+	// SHA1("password"), with no salt
+	bare, err := hex.DecodeString("5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8")
+	if err != nil {
+		t.Errorf("unexpected error decoding hex string: %e", err)
+	}
+
+	sha1Part, salt, err := Decode(bare)
+	if !errors.Is(err, ErrSliceTooShortSSHA1) || errors.Is(err, ErrSliceTooShortSHA1) {
+		t.Errorf("Decode() of %d bytes error = %v; expected only %v", len(bare), err, ErrSliceTooShortSSHA1)
+	}
+	if sha1Part != nil || salt != nil {
+		t.Errorf("Decode() of %d bytes = %x, %x; expected nil slices", len(bare), sha1Part, salt)
+	}
+	if err == nil || !strings.Contains(err.Error(), "no salt present") {
+		t.Errorf("Decode() of %d bytes error = %v; expected it to mention the missing salt", len(bare), err)
+	}
+	// the sentinel's own message is unchanged, for backwards compatibility
+	if result, expected := ErrSliceTooShortSSHA1.Error(), "slice too short to be a SSHA1 hash"; result != expected {
+		t.Errorf("ErrSliceTooShortSSHA1 message = %q; expected %q", result, expected)
+	}
+
+	if _, _, err := Decode(bare[:sha1.Size-1]); !errors.Is(err, ErrSliceTooShortSHA1) || errors.Is(err, ErrSliceTooShortSSHA1) {
+		t.Errorf("Decode() of %d bytes error = %v; expected only %v", sha1.Size-1, err, ErrSliceTooShortSHA1)
+	}
+}
+
+func TestNewSaltedEquivalence(t *testing.T) {
+	salt := []byte("g3N3r1c!")
+	data := []byte("Simplicity is prerequisite for reliability.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	g, err := crypto.NewSalted(sha1.New, salt)
+	if err != nil {
+		t.Errorf("crypto.NewSalted() returned unexpected error: %e", err)
+	}
+
+	c.Write(data)
+	g.Write(data)
+
+	if result, expected := g.Sum(nil), c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("crypto.NewSalted Sum result = %x; expected %x", result, expected)
+	}
+	if g.Size() != c.Size() || g.BlockSize() != c.BlockSize() {
+		t.Errorf("crypto.NewSalted sizes = %d/%d; expected %d/%d", g.Size(), g.BlockSize(), c.Size(), c.BlockSize())
+	}
+}
+
+func TestValidateStringCT(t *testing.T) {
+	// salt: "R*w.5Vmo"
+	stored := "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="
+
+	if result, err := ValidateStringCT(stored, []byte("You have to be odd to be number one.")); err != nil || !result {
+		t.Errorf("ValidateStringCT() = %t, %v; expected true, nil", result, err)
+	}
+	if result, err := ValidateStringCT(stored, []byte("You have to be odd to be number one!")); err != nil || result {
+		t.Errorf("ValidateStringCT() = %t, %v; expected false, nil", result, err)
+	}
+	if _, err := ValidateStringCT("{SSHA}%%%", nil); !errors.Is(err, ErrInvalidBase64) {
+		t.Errorf("ValidateStringCT() error = %v; expected %v", err, ErrInvalidBase64)
+	}
+	if _, err := ValidateStringCT("{SSHA1}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil); !errors.Is(err, ErrMalformedPrefix) {
+		t.Errorf("ValidateStringCT() error = %v; expected %v", err, ErrMalformedPrefix)
+	}
+}
+
+func TestSetSalt(t *testing.T) {
+	data := []byte("All that glitters is not gold.")
+	newSalt := []byte("n4pggXWL")
+
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write(data[:10])
+	c.Write(data[10:])
+	before := c.Sum(nil)
+
+	d := c.(*digest)
+	if err := d.SetSalt(newSalt); err != nil {
+		t.Errorf("method SetSalt() returned unexpected error: %e", err)
+	}
+	newSalt[0] = 'x' // the salt must have been copied
+
+	independent, err := NewWithSalt([]byte("n4pggXWL"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	independent.Write(data)
+
+	result, expected := c.Sum(nil), independent.Sum(nil)
+	if !bytes.Equal(result, expected) {
+		t.Errorf("Sum after SetSalt() = %x; expected %x", result, expected)
+	}
+	if bytes.Equal(result, before) {
+		t.Errorf("Sum after SetSalt() unchanged: %x", result)
+	}
+	if !bytes.Equal(c.(crypto.Salter).Salt(), []byte("n4pggXWL")) {
+		t.Errorf("Salt = %q; expected %q", c.(crypto.Salter).Salt(), "n4pggXWL")
+	}
+
+	for _, tc := range []struct {
+		salt     []byte
+		expected error
+	}{
+		{nil, ErrNilSalt},
+		{[]byte{}, ErrSaltTooShort},
+		{make([]byte, MaxSaltBytes+1), ErrSaltTooLong},
+	} {
+		if err := d.SetSalt(tc.salt); !errors.Is(err, tc.expected) {
+			t.Errorf("SetSalt() error = %v; expected %v", err, tc.expected)
+		}
+	}
+
+	prefixed, err := NewWithSaltPosition([]byte("R*w.5Vmo"), SaltPrefix)
+	if err != nil {
+		t.Errorf("method NewWithSaltPosition() returned unexpected error: %e", err)
+	}
+	if err := prefixed.(*digest).SetSalt([]byte("n4pggXWL")); !errors.Is(err, ErrSaltAlreadyHashed) {
+		t.Errorf("SetSalt() error = %v; expected %v", err, ErrSaltAlreadyHashed)
+	}
+}
+
+func TestResetWithNewSalt(t *testing.T) {
+	data := []byte("Stay hungry, stay foolish.")
+	salt := []byte("0ldS4lt!")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("discarded"))
+	c.Write(data)
+	before := c.Sum(nil)
+
+	if err := c.(crypto.SaltResetter).ResetWithNewSalt(); err != nil {
+		t.Errorf("method ResetWithNewSalt() returned unexpected error: %e", err)
+	}
+	c.Write(data)
+	after := c.Sum(nil)
+
+	if bytes.Equal(before, after) {
+		t.Errorf("Sum results before and after ResetWithNewSalt are identical: %x", after)
+	}
+	if c.SaltSize() != len(salt) {
+		t.Errorf("SaltSize after ResetWithNewSalt = %d; expected %d", c.SaltSize(), len(salt))
+	}
+	if !bytes.Equal(salt, []byte("0ldS4lt!")) {
+		t.Errorf("ResetWithNewSalt modified the caller's salt slice: %q", salt)
+	}
+
+	expected, err := Sum(data, c.(crypto.Salter).Salt())
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(after, expected) {
+		t.Errorf("Sum result after ResetWithNewSalt = %x; expected %x", after, expected)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	if result := c.Scheme(); result != "{SSHA}" {
+		t.Errorf("Scheme result = %s; expected %s", result, "{SSHA}")
+	}
+	if result := c.String(); !strings.HasPrefix(result, c.Scheme()) {
+		t.Errorf("String result %s does not start with Scheme %s", result, c.Scheme())
+	}
+}
+
+func TestSchemeWithOptions(t *testing.T) {
+	cases := []optionsCase{
+		{Options{}, "{SSHA}"},
+		{Options{Prefix: "{ssha}"}, "{ssha}"},
+		{Options{Prefix: "{ssha}", NoPrefix: true}, ""},
+	}
+
+	for _, c := range cases {
+		h, err := NewWithOptions(c.opts)
+		if err != nil {
+			t.Errorf("method NewWithOptions() returned unexpected error: %e", err)
+			continue
+		}
+		if result := h.Scheme(); result != c.expected {
+			t.Errorf("Scheme result = %q; expected %q", result, c.expected)
+		}
+		if result := h.String(); !strings.HasPrefix(result, h.Scheme()) {
+			t.Errorf("String result %s does not start with Scheme %s", result, h.Scheme())
+		}
+	}
+}
+
+func TestNewWithOptionsSaltAttempts(t *testing.T) {
+	// three weak salts exhaust a budget of three attempts, but not of four
+	r := &weakThenReader{n: 3, r: rand.Reader}
+	if _, err := NewWithOptions(Options{SaltSize: 8, Rand: r, SaltAttempts: 3}); !errors.Is(err, ErrWeakSalt) {
+		t.Errorf("NewWithOptions() error = %v; expected %v", err, ErrWeakSalt)
+	}
+	r = &weakThenReader{n: 3, r: rand.Reader}
+	if _, err := NewWithOptions(Options{SaltSize: 8, Rand: r, SaltAttempts: 4}); err != nil {
+		t.Errorf("method NewWithOptions() returned unexpected error: %e", err)
+	}
+}
+
+func TestConstructorsSetSalt(t *testing.T) {
+	constructors := map[string]func() (crypto.Hash, error){
+		"New":                 New,
+		"NewWithSalt":         func() (crypto.Hash, error) { return NewWithSalt([]byte("a")) },
+		"NewWithSaltPosition": func() (crypto.Hash, error) { return NewWithSaltPosition([]byte("a"), SaltPrefix) },
+		"NewForSaltSize":      func() (crypto.Hash, error) { return NewForSaltSize(MinSaltBytes) },
+		"NewWithRand":         func() (crypto.Hash, error) { return NewWithRand(rand.Reader, MinSaltBytes) },
+	}
+
+	for name, construct := range constructors {
+		c, err := construct()
+		if err != nil {
+			t.Errorf("method %s() returned unexpected error: %e", name, err)
+			continue
+		}
+		if result := c.SaltSize(); result < MinSaltBytes {
+			t.Errorf("%s() SaltSize result = %d; expected at least %d", name, result, MinSaltBytes)
+		}
+	}
+}
+
+func TestDigestWithoutSaltErrors(t *testing.T) {
+	d := new(digest)
+	d.Reset()
+	if err := d.ResetWithNewSalt(); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("ResetWithNewSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := d.MarshalBinary(); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("MarshalBinary() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+}
+
+func TestSumWithoutSaltPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Sum() on a digest with no salt did not panic")
+		}
+	}()
+	d := new(digest)
+	d.Reset()
+	d.Sum(nil)
+}
+
+type saltSizeOfCase struct {
+	encoded     string
+	expected    int
+	expectedErr error
+}
+
+func TestSaltSizeOf(t *testing.T) {
+	cases := []saltSizeOfCase{
+		// salt: "R*w.5Vmo"
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", 8, nil},
+		// salt: "R*w.5Vmo", no prefix
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", 8, nil},
+		// salt: "abcdefg"
+		{"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", 7, nil},
+		// salt: "x5yunfC]3rrjw*@VeBxNeW*oRp-PM>s*"
+		{"{SSHA}8UcT3hlkhDvq5UK08TAkOYVJrH14NXl1bmZDXTNycmp3KkBWZUJ4TmVXKm9ScC1QTT5zKg==", 32, nil},
+		// salt: "X"
+		{"{SSHA}aRvqrBMKC+JdxRfeTmORM009DzdY", 1, nil},
+		// a SHA-1 hash with no salt
+		{"{SSHA}mrUPJ9QgHbmyhIO6g8SOuvuyqhc=", 0, ErrSliceTooShortSSHA1},
+		// too short to be a SHA-1 hash
+		{"{SSHA}UgpBsp+JG7rM8x0=", 0, ErrSliceTooShortSHA1},
+		{"{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", 0, ErrMalformedPrefix},
+		{"{SSHA}not*valid*base64!", 0, ErrInvalidBase64},
+	}
+
+	for _, c := range cases {
+		result, err := SaltSizeOf(c.encoded)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("SaltSizeOf(%q) error = %v; expected %v", c.encoded, err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+		if result != c.expected {
+			t.Errorf("SaltSizeOf(%q) = %d; expected %d", c.encoded, result, c.expected)
+		}
+
+		// the reported size can be fed back into NewForSaltSize
+		d, err := NewForSaltSize(result)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		if d.SaltSize() != c.expected {
+			t.Errorf("NewForSaltSize(%d) SaltSize = %d; expected %d", result, d.SaltSize(), c.expected)
+		}
+	}
+}
+
+type validateWithSaltCase struct {
+	ssha1HashString string
+	sample          []byte
+	expected        bool
+}
+
+func TestValidateWithSalt(t *testing.T) {
+	cases := []validateWithSaltCase{
+		// salt: "abcdefg"
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667", []byte("1234567890"), true},
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667", []byte("123456789"), false},
+		// salt: "R*w.5Vmo"
+		{"87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f", []byte("You have to be odd to be number one."), true},
+		{"87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f", []byte("You have to be odd to be number two."), false},
+	}
+
+	for _, c := range cases {
+		ssha1Hash, err := hex.DecodeString(c.ssha1HashString)
+		if err != nil {
+			t.Errorf("unable to convert hex string '%s' to []byte.", err)
+		}
+
+		// the salt is stored separately from the bare SHA-1 digest
+		sha1Part, salt, err := Decode(ssha1Hash)
+		if err != nil {
+			t.Errorf("method Decode() returned unexpected error: %e", err)
+			continue
+		}
+
+		result, err := ValidateWithSalt(sha1Part, salt, c.sample)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("validation test failed for test case %v", c)
+		}
+	}
+}
+
+func TestValidateWithSaltErrors(t *testing.T) {
+	salt := []byte("abcdefg")
+	for _, size := range []int{0, sha1.Size - 1, sha1.Size + 1, sha1.Size + len(salt)} {
+		if _, err := ValidateWithSalt(make([]byte, size), salt, nil); !errors.Is(err, ErrInvalidSHA1Size) {
+			t.Errorf("ValidateWithSalt() error = %v for %d bytes; expected %v", err, size, ErrInvalidSHA1Size)
+		}
+	}
+	if _, err := ValidateWithSalt(make([]byte, sha1.Size), nil, nil); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("ValidateWithSalt() error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := ValidateWithSalt(make([]byte, sha1.Size), []byte{}, nil); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("ValidateWithSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := ValidateWithSalt(make([]byte, sha1.Size), make([]byte, MaxSaltBytes+1), nil); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("ValidateWithSalt() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+}
+
+type hexValidateCase struct {
+	hexHash     string
+	sample      []byte
+	expected    bool
+	expectedErr error
+}
+
+func TestHexValidate(t *testing.T) {
+	cases := []hexValidateCase{
+		// salt: "abcdefg"
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667", []byte("1234567890"), true, nil},
+		{"8417680C09644DF743D7CEA1366FBE13A31B2D5E61626364656667", []byte("1234567890"), true, nil},
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667", []byte("123456789"), false, nil},
+		// salt: "R*w.5Vmo"
+		{"87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f", []byte("You have to be odd to be number one."), true, nil},
+		// odd length
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e6162636465666", []byte("1234567890"), false, ErrInvalidHex},
+		// not hex
+		{"8417680c09644df743d7cea1366fbe13a31b2d5e616263646566zz", []byte("1234567890"), false, ErrInvalidHex},
+		// base-64 rather than hex
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", nil, false, ErrInvalidHex},
+		// valid hex, but too short to be a SSHA1 hash
+		{"520d41b29f891bbaccf31d", nil, false, ErrSliceTooShortSHA1},
+		{"9ab50f27d4201db9b28483ba83c48ebafbb2aa17", nil, false, ErrSliceTooShortSSHA1},
+	}
+
+	for _, c := range cases {
+		result, err := HexValidate(c.hexHash, c.sample)
+		if c.expectedErr != nil {
+			if !errors.Is(err, c.expectedErr) {
+				t.Errorf("HexValidate(%q) error = %v; expected %v", c.hexHash, err, c.expectedErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("validation test failed for test case %v", c)
+		}
+	}
+}
+
+func TestSumSize(t *testing.T) {
+	for _, size := range []int{MinSaltBytes, 8, DefaultNumSaltBytes, 32, MaxSaltBytes} {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		if result := SumSize(size); result != c.Size() {
+			t.Errorf("SumSize(%d) = %d; expected %d", size, result, c.Size())
+		}
+	}
+}
+
+type validateAnyStoredCase struct {
+	stored        []string
+	sample        []byte
+	expected      bool
+	expectedIndex int
+}
+
+func TestValidateAnyStored(t *testing.T) {
+	history := []string{
+		// salt: "abcdefg"
+		"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn",
+		// salt: "R*w.5Vmo"
+		"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==",
+		// salt: "ajE94aZM"
+		"{SSHA}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==",
+	}
+
+	cases := []validateAnyStoredCase{
+		{history, []byte("You have to be odd to be number one."), true, 1},
+		{history, []byte("1234567890"), true, 0},
+		{history, []byte("When life gives you lemons, make lemonade."), true, 2},
+		{history, []byte("correct horse battery staple"), false, -1},
+		// the first of several matches is reported
+		{append(history, history[1]), []byte("You have to be odd to be number one."), true, 1},
+		{nil, []byte("1234567890"), false, -1},
+	}
+
+	for _, c := range cases {
+		matched, index, err := ValidateAnyStored(c.stored, c.sample)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if matched != c.expected || index != c.expectedIndex {
+			t.Errorf("ValidateAnyStored(%q) = %t, %d; expected %t, %d", c.sample, matched, index, c.expected, c.expectedIndex)
+		}
+	}
+
+	stored := append([]string{history[0], "{SSHA}not*valid*base64!"}, history[1:]...)
+	matched, index, err := ValidateAnyStored(stored, []byte("1234567890"))
+	if !errors.Is(err, ErrInvalidBase64) {
+		t.Errorf("ValidateAnyStored() error = %v; expected %v", err, ErrInvalidBase64)
+	}
+	if matched || index != -1 {
+		t.Errorf("ValidateAnyStored() = %t, %d on error; expected false, -1", matched, index)
+	}
+}
+
+func TestSharedSaltConstants(t *testing.T) {
+	if DefaultNumSaltBytes != crypto.DefaultNumSaltBytes {
+		t.Errorf("DefaultNumSaltBytes = %d; expected crypto.DefaultNumSaltBytes (%d)", DefaultNumSaltBytes, crypto.DefaultNumSaltBytes)
+	}
+	if MinSaltBytes != crypto.MinSaltBytes {
+		t.Errorf("MinSaltBytes = %d; expected crypto.MinSaltBytes (%d)", MinSaltBytes, crypto.MinSaltBytes)
+	}
+	if MaxSaltBytes != crypto.MaxSaltBytes {
+		t.Errorf("MaxSaltBytes = %d; expected crypto.MaxSaltBytes (%d)", MaxSaltBytes, crypto.MaxSaltBytes)
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	if c.SaltSize() != crypto.DefaultNumSaltBytes {
+		t.Errorf("New() salt size = %d; expected %d", c.SaltSize(), crypto.DefaultNumSaltBytes)
+	}
+}
@@ -0,0 +1,71 @@
+package ssha1
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+)
+
+// StoredHash is a parsed SSHA1 hash, as kept in a credential store. It
+// bundles the parsing, validation and formatting of stored hashes behind
+// one type. The zero value is not a valid StoredHash; use ParseStoredHash
+// or StoredHashFromBytes.
+type StoredHash struct {
+	ssha1Hash []byte
+}
+
+// ParseStoredHash parses a base-64 encoded SSHA1 hash, accepted in any form
+// supported by ValidateString. The same length rules as for Validate
+// apply.
+func ParseStoredHash(s string) (StoredHash, error) {
+	ssha1Hash, err := parseString(s)
+	if err != nil {
+		return StoredHash{}, err
+	}
+	return storedHash(ssha1Hash)
+}
+
+// StoredHashFromBytes returns the StoredHash for the specified raw SSHA1
+// hash, SHA-1 digest followed by salt. The same length rules as for
+// Validate apply. The bytes are copied.
+func StoredHashFromBytes(ssha1Hash []byte) (StoredHash, error) {
+	return storedHash(append([]byte(nil), ssha1Hash...))
+}
+
+func storedHash(ssha1Hash []byte) (StoredHash, error) {
+	if _, _, err := Decode(ssha1Hash); err != nil {
+		return StoredHash{}, err
+	}
+	return StoredHash{ssha1Hash: ssha1Hash}, nil
+}
+
+// Digest returns a copy of the 20-byte SHA-1 digest.
+func (s StoredHash) Digest() []byte {
+	return append([]byte(nil), s.ssha1Hash[:sha1.Size]...)
+}
+
+// Salt returns a copy of the salt.
+func (s StoredHash) Salt() []byte {
+	return append([]byte(nil), s.ssha1Hash[sha1.Size:]...)
+}
+
+// Scheme returns the scheme prefix used by String, "{SSHA}".
+func (s StoredHash) Scheme() string { return scheme }
+
+// Bytes returns a copy of the raw SSHA1 hash, SHA-1 digest followed by
+// salt.
+func (s StoredHash) Bytes() []byte {
+	return append([]byte(nil), s.ssha1Hash...)
+}
+
+// Validate returns true if the SSHA1 hash of the sample matches the stored
+// hash; false, otherwise. The hashes are compared in constant time.
+func (s StoredHash) Validate(sample []byte) (bool, error) {
+	return Validate(s.ssha1Hash, sample)
+}
+
+// String returns the base-64 encoded string representation of the stored
+// hash, prefixed with "{SSHA}".
+func (s StoredHash) String() string { // fmt.Stringer interface
+	return fmt.Sprintf(outputFmt, base64.StdEncoding.EncodeToString(s.ssha1Hash))
+}
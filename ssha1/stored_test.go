@@ -0,0 +1,106 @@
+package ssha1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+type storedHashCase struct {
+	encoded  string
+	canon    string
+	password []byte
+	salt     []byte
+}
+
+func TestParseStoredHash(t *testing.T) {
+	cases := []storedHashCase{
+		{"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), []byte("R*w.5Vmo")},
+		// no prefix and no padding are canonicalized by String
+		{"h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw", "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", []byte("You have to be odd to be number one."), []byte("R*w.5Vmo")},
+		{"{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", "{SSHA}hBdoDAlkTfdD186hNm++E6MbLV5hYmNkZWZn", []byte("1234567890"), []byte("abcdefg")},
+		{"{SSHA}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==", "{SSHA}KUrFi4tmLo9gT89upMoBEF1YAINhakU5NGFaTQ==", []byte("When life gives you lemons, make lemonade."), []byte("ajE94aZM")},
+	}
+
+	for _, c := range cases {
+		s, err := ParseStoredHash(c.encoded)
+		if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+			continue
+		}
+
+		if result, err := s.Validate(c.password); err != nil || !result {
+			t.Errorf("Validate(%q) = %t, %v; expected true, nil", c.password, result, err)
+		}
+		if result, err := s.Validate(append(c.password, '!')); err != nil || result {
+			t.Errorf("Validate(%q) = %t, %v; expected false, nil", append(c.password, '!'), result, err)
+		}
+		if result := s.Salt(); !bytes.Equal(result, c.salt) {
+			t.Errorf("Salt result = %q; expected %q", result, c.salt)
+		}
+		if result := s.Scheme(); result != "{SSHA}" {
+			t.Errorf("Scheme result = %s; expected {SSHA}", result)
+		}
+		if result := s.String(); result != c.canon {
+			t.Errorf("String result = %s; expected %s", result, c.canon)
+		}
+
+		// round trip through the string and raw forms
+		again, err := ParseStoredHash(s.String())
+		if err != nil || !bytes.Equal(again.Bytes(), s.Bytes()) {
+			t.Errorf("ParseStoredHash(%q) = %x, %v; expected %x, nil", s.String(), again.Bytes(), err, s.Bytes())
+		}
+		fromBytes, err := StoredHashFromBytes(append(s.Digest(), s.Salt()...))
+		if err != nil || fromBytes.String() != c.canon {
+			t.Errorf("StoredHashFromBytes() = %s, %v; expected %s, nil", fromBytes, err, c.canon)
+		}
+	}
+}
+
+func TestStoredHashFromBytes(t *testing.T) {
+	// salt: "abcdefg"
+	ssha1Hash, err := hex.DecodeString("8417680c09644df743d7cea1366fbe13a31b2d5e61626364656667")
+	if err != nil {
+		t.Errorf("unable to convert hex string '%s' to []byte.", err)
+	}
+
+	s, err := StoredHashFromBytes(ssha1Hash)
+	if err != nil {
+		t.Errorf("method StoredHashFromBytes() returned unexpected error: %e", err)
+	}
+
+	// the StoredHash does not share memory with its input or its results
+	ssha1Hash[0] ^= 0xff
+	s.Digest()[0] ^= 0xff
+	s.Salt()[0] ^= 0xff
+	s.Bytes()[1] ^= 0xff
+
+	if result, err := s.Validate([]byte("1234567890")); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+	if result := hex.EncodeToString(s.Digest()); result != "8417680c09644df743d7cea1366fbe13a31b2d5e" {
+		t.Errorf("Digest result = %s; expected %s", result, "8417680c09644df743d7cea1366fbe13a31b2d5e")
+	}
+}
+
+func TestParseStoredHashErrors(t *testing.T) {
+	cases := []struct {
+		encoded     string
+		expectedErr error
+	}{
+		{"{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", ErrMalformedPrefix},
+		{"{SSHA}not*valid*base64!", ErrInvalidBase64},
+		{"{SSHA}UgpBsp+JG7rM8x0=", ErrSliceTooShortSHA1},
+		{"{SSHA}mrUPJ9QgHbmyhIO6g8SOuvuyqhc=", ErrSliceTooShortSSHA1},
+	}
+
+	for _, c := range cases {
+		if _, err := ParseStoredHash(c.encoded); !errors.Is(err, c.expectedErr) {
+			t.Errorf("ParseStoredHash(%q) error = %v; expected %v", c.encoded, err, c.expectedErr)
+		}
+	}
+	if _, err := StoredHashFromBytes(nil); !errors.Is(err, ErrSliceTooShortSHA1) {
+		t.Errorf("StoredHashFromBytes() error = %v; expected %v", err, ErrSliceTooShortSHA1)
+	}
+}
@@ -0,0 +1,85 @@
+package ssha1
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+)
+
+// Validator checks samples against a single stored SSHA1 hash. The salt is
+// extracted once, when the Validator is created, and the underlying digest
+// is reused for every check, which makes a Validator cheaper than repeated
+// calls to Validate when many samples are checked against the same hash.
+//
+// A Validator implements encoding.TextMarshaler and
+// encoding.TextUnmarshaler using the "{SSHA}" string form, so a stored hash
+// can be kept in JSON or YAML documents. As the original data cannot be
+// recovered from a stored hash, a Validator can only check samples; it
+// cannot be written to.
+//
+// A Validator is not safe for concurrent use by multiple goroutines.
+type Validator struct {
+	stored []byte
+	d      *digest
+	buf    []byte
+}
+
+// NewValidator returns a new Validator for the specified SSHA1 hash. The
+// same length rules as for Validate apply. The hash is copied, so the
+// caller may modify ssha1Hash afterwards.
+func NewValidator(ssha1Hash []byte) (*Validator, error) {
+	v := new(Validator)
+	if err := v.init(append([]byte(nil), ssha1Hash...)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *Validator) init(stored []byte) error {
+	_, salt, err := Decode(stored)
+	if err != nil {
+		return err
+	}
+
+	d, err := NewWithSalt(salt)
+	if err != nil {
+		return err
+	}
+
+	v.stored = stored
+	v.d = d.(*digest)
+	v.buf = make([]byte, 0, len(stored))
+	return nil
+}
+
+// Check returns true if the SSHA1 hash of the sample matches the stored
+// hash; false, otherwise. The hashes are compared in constant time. Check
+// may be called any number of times.
+func (v *Validator) Check(sample []byte) bool {
+	v.d.reset()
+	v.d.Write(sample)
+	v.buf = v.d.Sum(v.buf[:0])
+
+	return subtle.ConstantTimeCompare(v.stored, v.buf) == 1
+}
+
+// String returns the base-64 encoded string representation of the stored
+// hash, prefixed with "{SSHA}".
+func (v *Validator) String() string { // fmt.Stringer interface
+	return fmt.Sprintf(outputFmt, base64.StdEncoding.EncodeToString(v.stored))
+}
+
+// MarshalText returns the stored hash in the form produced by String.
+func (v *Validator) MarshalText() ([]byte, error) { // encoding.TextMarshaler interface
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText replaces the stored hash with the one encoded in text,
+// which is accepted in any form supported by ValidateString.
+func (v *Validator) UnmarshalText(text []byte) error { // encoding.TextUnmarshaler interface
+	stored, err := parseString(string(text))
+	if err != nil {
+		return err
+	}
+	return v.init(stored)
+}
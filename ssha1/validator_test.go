@@ -0,0 +1,164 @@
+package ssha1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type validatorCase struct {
+	sample   []byte
+	expected bool
+}
+
+func TestValidator(t *testing.T) {
+	// salt: "R*w.5Vmo"
+	stored, err := hex.DecodeString("87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f")
+	if err != nil {
+		t.Fatalf("hex.DecodeString() returned unexpected error: %e", err)
+	}
+	v, err := NewValidator(stored)
+	if err != nil {
+		t.Fatalf("method NewValidator() returned unexpected error: %e", err)
+	}
+
+	// modifying the caller's slice must not affect the validator
+	stored[0] ^= 0xff
+
+	cases := []validatorCase{
+		{[]byte("You have to be odd to be number one."), true},
+		{[]byte("You have to be odd to be number two."), false},
+		{[]byte(""), false},
+		{[]byte("You have to be odd to be number one."), true},
+	}
+
+	for _, c := range cases {
+		if result := v.Check(c.sample); result != c.expected {
+			t.Errorf("Check(%q) result = %t; expected %t", c.sample, result, c.expected)
+		}
+	}
+}
+
+func TestNewValidatorErrors(t *testing.T) {
+	cases := []struct {
+		stored      []byte
+		expectedErr error
+	}{
+		{nil, ErrSliceTooShortSHA1},
+		{make([]byte, 20), ErrSliceTooShortSSHA1},
+		{make([]byte, 20+MaxSaltBytes+1), ErrSaltTooLong},
+	}
+
+	for _, c := range cases {
+		if _, err := NewValidator(c.stored); !errors.Is(err, c.expectedErr) {
+			t.Errorf("NewValidator() error = %v for %d bytes; expected %v", err, len(c.stored), c.expectedErr)
+		}
+	}
+}
+
+func BenchmarkValidator(b *testing.B) {
+	for _, in := range benchInputs {
+		for _, size := range benchSaltSizes {
+			stored, err := Sum(in.data, bytes.Repeat([]byte{'s'}, size))
+			if err != nil {
+				b.Fatalf("method Sum() returned unexpected error: %e", err)
+			}
+			b.Run(fmt.Sprintf("Validate/%s/salt%d", in.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(in.data)))
+				for i := 0; i < b.N; i++ {
+					Validate(stored, in.data)
+				}
+			})
+			b.Run(fmt.Sprintf("Validator/%s/salt%d", in.name, size), func(b *testing.B) {
+				v, err := NewValidator(stored)
+				if err != nil {
+					b.Fatalf("method NewValidator() returned unexpected error: %e", err)
+				}
+				b.ReportAllocs()
+				b.SetBytes(int64(len(in.data)))
+				for i := 0; i < b.N; i++ {
+					v.Check(in.data)
+				}
+			})
+		}
+	}
+}
+
+func TestValidatorString(t *testing.T) {
+	// salt: "R*w.5Vmo"
+	stored, err := hex.DecodeString("87e5962a980b63f390a2b9feb87022ec6b2bf4b6522a772e35566d6f")
+	if err != nil {
+		t.Fatalf("hex.DecodeString() returned unexpected error: %e", err)
+	}
+	v, err := NewValidator(stored)
+	if err != nil {
+		t.Fatalf("method NewValidator() returned unexpected error: %e", err)
+	}
+
+	expected := "{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="
+	if result := v.String(); result != expected {
+		t.Errorf("String result = %s; expected %s", result, expected)
+	}
+}
+
+type credential struct {
+	User     string     `json:"user"`
+	Password *Validator `json:"password"`
+}
+
+func TestValidatorJSON(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Fatalf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("You have to be odd to be number one."))
+	v, err := NewValidator(c.Sum(nil))
+	if err != nil {
+		t.Fatalf("method NewValidator() returned unexpected error: %e", err)
+	}
+
+	data, err := json.Marshal(credential{User: "alice", Password: v})
+	if err != nil {
+		t.Fatalf("json.Marshal() returned unexpected error: %e", err)
+	}
+	expected := `{"user":"alice","password":"{SSHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw=="}`
+	if string(data) != expected {
+		t.Errorf("json.Marshal result = %s; expected %s", data, expected)
+	}
+
+	var result credential
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("json.Unmarshal() returned unexpected error: %e", err)
+	}
+	if result.User != "alice" {
+		t.Errorf("json.Unmarshal user = %s; expected alice", result.User)
+	}
+	if !result.Password.Check([]byte("You have to be odd to be number one.")) {
+		t.Errorf("Check() failed after JSON round trip")
+	}
+	if result.Password.Check([]byte("You have to be odd to be number two.")) {
+		t.Errorf("Check() matched wrong sample after JSON round trip")
+	}
+}
+
+func TestValidatorUnmarshalTextErrors(t *testing.T) {
+	cases := []struct {
+		text        string
+		expectedErr error
+	}{
+		{"{SHA}h+WWKpgLY/OQorn+uHAi7Gsr9LZSKncuNVZtbw==", ErrMalformedPrefix},
+		{"{SSHA}not*valid*base64!", ErrInvalidBase64},
+		{"{SSHA}UgpBsp+JG7rM8x0=", ErrSliceTooShortSHA1},
+	}
+
+	for _, c := range cases {
+		var v Validator
+		if err := v.UnmarshalText([]byte(c.text)); !errors.Is(err, c.expectedErr) {
+			t.Errorf("UnmarshalText(%q) error = %v; expected %v", c.text, err, c.expectedErr)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package ssha256
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+const (
+	// DefaultNumSaltBytes specifies the default number of salt bytes
+	// used when creating via New().
+	DefaultNumSaltBytes int = crypto.DefaultNumSaltBytes
+
+	// MinSaltBytes specifies the minimum allowed number of salt bytes.
+	MinSaltBytes int = crypto.MinSaltBytes
+
+	// MaxSaltBytes specifies the maximum allowed number of salt bytes.
+	MaxSaltBytes int = crypto.MaxSaltBytes
+
+	// BlockSize specifies the block size of the SHA-256 hash in bytes.
+	BlockSize = sha256.BlockSize
+
+	scheme    string = crypto.SchemeSSHA256
+	outputFmt string = scheme + "%s"
+)
+
+// Errors returned by this package.
+var (
+	// ErrNilSalt is returned when NewWithSalt is passed a nil salt.
+	ErrNilSalt = crypto.ErrNilSalt
+
+	// ErrSaltTooShort is returned when a salt is shorter than MinSaltBytes.
+	ErrSaltTooShort = crypto.ErrSaltTooShort
+
+	// ErrSaltTooLong is returned when a salt is longer than MaxSaltBytes.
+	ErrSaltTooLong = crypto.ErrSaltTooLong
+
+	// ErrWeakSalt is returned when the randomness source repeatedly
+	// produces salts of identical bytes.
+	ErrWeakSalt = crypto.ErrWeakSalt
+
+	// ErrSliceTooShortSHA256 is returned when a slice is too short to hold a
+	// SHA-256 hash.
+	ErrSliceTooShortSHA256 = errors.New("slice too short for a SHA-256 hash")
+
+	// ErrSliceTooShortSSHA256 is returned when a slice holds a SHA-256 hash but
+	// no salt.
+	ErrSliceTooShortSSHA256 = errors.New("slice too short to be a SSHA256 hash")
+)
+
+func init() {
+	crypto.Register(crypto.SchemeSSHA256, Validate)
+}
+
+// New returns a new hash.Hash  with the default salt size (20 bytes).
+// The salt will be generated using the crypto/rand package.
+func New() (crypto.Hash, error) {
+	return NewForSaltSize(DefaultNumSaltBytes)
+}
+
+// NewWithSalt returns a new hash.Hash with the specified salt.
+// Salt size must be between 1 and 1024 bytes. A nil salt yields ErrNilSalt
+// and an empty one ErrSaltTooShort.
+func NewWithSalt(salt []byte) (crypto.Hash, error) {
+	h, err := crypto.NewSalted(sha256.New, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &digest{h}, nil
+}
+
+// NewForSaltSize returns a new hash.Hash with the specified salt size.
+// Salt size must be between 1 and 1024 bytes. The salt will be generated
+// using the crypto/rand package.
+func NewForSaltSize(numSaltBytes int) (crypto.Hash, error) {
+	if numSaltBytes < MinSaltBytes {
+		return nil, ErrSaltTooShort
+	}
+	if numSaltBytes > MaxSaltBytes {
+		return nil, ErrSaltTooLong
+	}
+	salt := make([]byte, numSaltBytes)
+	if err := crypto.ReadSalt(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	return NewWithSalt(salt)
+}
+
+// SumSize returns the number of bytes in a SSHA256 checksum with a salt of
+// saltLen bytes, i.e. the Size of a hash.Hash with such a salt. It can be
+// used to size buffers, e.g. for crypto.SumTo, without creating a hash.
+func SumSize(saltLen int) int {
+	return sha256.Size + saltLen
+}
+
+// Sum returns the SSHA256 checksum of the data.
+func Sum(data, salt []byte) ([]byte, error) {
+	var d hash.Hash
+	if salt == nil {
+		d0, err := New()
+		if err != nil {
+			return nil, err
+		}
+		d = d0
+	} else {
+		d0, err := NewWithSalt(salt)
+		if err != nil {
+			return nil, err
+		}
+		d = d0
+	}
+
+	d.Write(data)
+	return d.Sum(nil), nil
+}
+
+// Validate returns true if the SSHA256 hash of the sample matches the
+// specified SSHA256 hash; false, otherwise. The hashes are compared in
+// constant time to avoid leaking timing information.
+func Validate(ssha256Hash, sample []byte) (bool, error) {
+	length := len(ssha256Hash)
+	if length < sha256.Size {
+		return false, ErrSliceTooShortSHA256
+	}
+
+	saltSize := length - sha256.Size
+	if saltSize == 0 {
+		return false, ErrSliceTooShortSSHA256
+	}
+
+	salt := ssha256Hash[length-saltSize:]
+	d, err := NewWithSalt(salt)
+	if err != nil {
+		return false, err
+	}
+
+	d.Write(sample)
+	result := d.Sum(nil)
+
+	return subtle.ConstantTimeCompare(ssha256Hash, result) == 1, nil
+}
+
+// #########################################################
+
+// digest adds the "{SSHA256}" scheme to the generic salted hash provided by
+// crypto.NewSalted.
+type digest struct {
+	crypto.Hash
+}
+
+// Salt returns a copy of the salt. Modifying the returned slice does not
+// affect the digest.
+func (d *digest) Salt() []byte { // crypto.Salter interface
+	return d.Hash.(crypto.Salter).Salt()
+}
+
+// ResetWithNewSalt resets the digest to its initial state and replaces the
+// salt with a new random one of the same size, generated via the
+// crypto/rand package.
+func (d *digest) ResetWithNewSalt() error { // crypto.SaltResetter interface
+	return d.Hash.(crypto.SaltResetter).ResetWithNewSalt()
+}
+
+// Clone returns an independent copy of the digest, including its salt
+// and any data written so far.
+func (d *digest) Clone() crypto.Hash { // crypto.Cloner interface
+	return &digest{d.Hash.(crypto.Cloner).Clone()}
+}
+
+// Scheme returns the scheme prefix used by String, "{SSHA256}".
+func (d *digest) Scheme() string { return scheme } // crypto.Hash interface
+
+// String returns the base-64 encoded string representation of
+// the SSHA256 sum, prefixed with "{SSHA256}".
+func (d *digest) String() string { // fmt.Stringer interface
+	return d.StringWithPrefix(scheme)
+}
+
+// StringWithPrefix returns the base-64 encoded string representation of
+// the SSHA256 sum, prefixed with the specified prefix instead of "{SSHA256}".
+// This is useful for tools that expect e.g. a lowercase scheme or no
+// prefix at all.
+func (d *digest) StringWithPrefix(prefix string) string { // crypto.PrefixStringer interface
+	return d.Hash.(crypto.PrefixStringer).StringWithPrefix(prefix)
+}
+
+// URLString returns the base-64 encoded string representation of the SSHA256
+// sum using the URL and filename safe alphabet (RFC 4648), prefixed with
+// "{SSHA256}". Where String uses '+' and '/', URLString uses '-' and '_', so
+// the two forms must not be mixed when validating.
+func (d *digest) URLString() string { // crypto.URLStringer interface
+	sum := d.Sum(nil)
+	return fmt.Sprintf(outputFmt, base64.URLEncoding.EncodeToString(sum))
+}
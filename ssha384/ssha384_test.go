@@ -0,0 +1,580 @@
+package ssha384
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strings"
+	"testing"
+
+	"github.com/kristinjeanna/crypto"
+)
+
+type sumCase struct {
+	plaintext         []byte
+	salt              []byte
+	expectedHexString string
+}
+
+func TestSum(t *testing.T) {
+	sumCases := []sumCase{
+		{[]byte("supercalifragilisticexpialidocious"), []byte("n4pggXWL"), "2d33e089d52ccc1f8a2952113f178783880e145f1186d129812c205a9e350c7a37317db169bd0978f9e0a192095dd3d36e3470676758574c"},
+		{[]byte("abcdefghijklmnopqrstuvwxyz"), []byte("K218iReB"), "53d09b3b03b67d0442f7483dd0b29092e00d3c73b3acbe01506792ddf06e8f843e9d7446803e28a864a0a09d84c88f794b32313869526542"},
+		{[]byte("All things are strange which are worth knowing."), nil, ""}, // coverage
+		{[]byte("Who you are authentically is alright."), []byte{}, ""},      // coverage
+	}
+
+	for _, c := range sumCases {
+		switch {
+		case c.salt == nil: // for coverage
+			Sum(c.plaintext, c.salt)
+		case len(c.salt) == 0: // should produce err due to 0-length salt
+			_, err := Sum(c.plaintext, c.salt)
+			if err == nil {
+				t.Errorf("method Sum() failed to return expected error")
+			}
+		default:
+			result, err := Sum(c.plaintext, c.salt)
+			if err != nil {
+				t.Errorf("method Sum() returned unexpected error: %e", err)
+			}
+			resultString := hex.EncodeToString(result)
+			if resultString != c.expectedHexString {
+				t.Errorf("result = %s; expected %s", resultString, c.expectedHexString)
+			}
+		}
+	}
+}
+
+type sizeCase struct {
+	newMethod   string
+	h           hash.Hash
+	errFromNew  error
+	expected    int
+	expectError bool
+}
+
+func setUpSizeCases() []sizeCase {
+	var c1 sizeCase
+	c1.newMethod = "New()"
+	c1.h, c1.errFromNew = New()
+	c1.expected = sha512.Size384 + DefaultNumSaltBytes
+	c1.expectError = false
+
+	var c2 sizeCase
+	c2.newMethod = "NewForSaltSize()"
+	c2.h, c2.errFromNew = NewForSaltSize(32)
+	c2.expected = sha512.Size384 + 32
+	c2.expectError = false
+
+	var c3 sizeCase
+	c3.newMethod = "NewForSaltSize()"
+	c3.h, c3.errFromNew = NewForSaltSize(0) // invalid salt size
+	c3.expected = 0
+	c3.expectError = true
+
+	var c4 sizeCase
+	salt1 := []byte("2cM6D2WitazRL5MD")
+	c4.newMethod = "NewWithSalt()"
+	c4.h, c4.errFromNew = NewWithSalt(salt1)
+	c4.expected = sha512.Size384 + len(salt1)
+	c4.expectError = false
+
+	cases := make([]sizeCase, 0)
+	cases = append(cases, c1, c2, c3, c4)
+
+	return cases
+}
+
+func TestSize(t *testing.T) {
+	cases := setUpSizeCases()
+
+	for _, c := range cases {
+		if c.expectError {
+			if c.errFromNew == nil {
+				t.Errorf("expected error but none returned for test case: %v", c)
+			}
+		} else if c.errFromNew != nil {
+			t.Errorf("%s returned unexpected error: %e", c.newMethod, c.errFromNew)
+		} else if result := c.h.Size(); result != c.expected {
+			t.Errorf("for test case %v: Size = %d; expected %d", c, result, c.expected)
+		}
+	}
+}
+
+type validateCase struct {
+	ssha384HashString string
+	sample            []byte
+	expected          bool
+	expectError       bool
+}
+
+func TestValidate(t *testing.T) {
+	cases := []validateCase{
+		// salt: "abcdefg"
+		{"95f66178acb6bdc0b45b4edc9c8d783f7b2a428832f052e8dceba8d0d77e1755e6a2f5d7e8899c431b970f8ac5d1c26861626364656667", []byte("1234567890"), true, false},
+		// salt: "abcdefg"
+		{"95f66178acb6bdc0b45b4edc9c8d783f7b2a428832f052e8dceba8d0d77e1755e6a2f5d7e8899c431b970f8ac5d1c26861626364656667", []byte("123456789"), false, false},
+		// salt: "x5yunfC]3rrjw*@VeBxNeW*oRp-PM>s*"
+		{"38b9e64a32bb58ce658cf2418f8816d9d678387c781c3460fa18ed6b0d1b2468862247e20899c7ebe1dc29327d1070ed783579756e66435d3372726a772a40566542784e65572a6f52702d504d3e732a", []byte("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."), true, false},
+		// salt: "X"
+		{"a02f2ef9f4147d041eb8688605d9dc617c0c518c62130236e9bc269c00b864fa5c239f44747e0e1794a378d3e01abc2358", []byte("protean-pith-anodyne-accolade-snare"), true, false},
+		// too short to be at least a SHA-384 hash
+		{"a4abd4448c49562d828115d13a1fccea927f52b4d5459297f8b43e42da89238b", nil, false, true},
+		// long enough to be at least a SHA-384 hash, but lacks at least 1 salt byte
+		{"652d05f05e98924f5e6e79456606c700307fd82720afd9729d020e3370ed0948a6d7e99a3c815b9e62a0f14d9884fac6", nil, false, true},
+	}
+
+	for _, c := range cases {
+		ssha384Hash, err := hex.DecodeString(c.ssha384HashString)
+		if err != nil {
+			t.Errorf("unable to convert hex string '%s' to []byte.", err)
+		}
+
+		result, err := Validate(ssha384Hash, c.sample)
+		if c.expectError {
+			if err == nil {
+				t.Errorf("expected error but none returned for test case: %v", c)
+			}
+		} else if err != nil {
+			t.Errorf("unexpected error (%e) for returned for test case: %v", err, c)
+		}
+		if result != c.expected {
+			t.Errorf("validation test failed for test case %v", c)
+		}
+	}
+}
+
+func TestBlockSize(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	if result := c.BlockSize(); result != BlockSize {
+		t.Errorf("BlockSize result = %d; expected %d", result, BlockSize)
+	}
+}
+
+func TestHexString(t *testing.T) {
+	c, err := NewWithSalt([]byte("ajE94aZM"))
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	expected := "93431080c1350cd64ec0cca8c39ec6dca0a4d5da431599f0df3650874fb9f15de0c5d8e62b7c982df2feb49645c428bb616a453934615a4d"
+
+	c.Write([]byte("When life gives you lemons, make lemonade."))
+
+	if result := c.HexString(); result != expected {
+		t.Errorf("HexString result = %s; expected %s", result, expected)
+	}
+}
+
+func TestString(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	expected := "{SSHA384}6MxBeLHC2bHcVYTIHQV7xyG5i5cpXrn2sZ3uihMj0OZoxbJ1PVSmzH2XhbMmhMF7Uip3LjVWbW8="
+
+	c.Write([]byte("You have to be odd to be number one."))
+
+	if result := c.String(); result != expected {
+		t.Errorf("String result = %s; expected %s", result, expected)
+	}
+}
+
+func TestSumDoesNotChangeState(t *testing.T) {
+	salt := []byte("tH3g5qLx")
+	first := []byte("The quick brown fox ")
+	second := []byte("jumps over the lazy dog.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write(first)
+	sum1 := c.Sum(nil)
+	c.Write(second)
+	sum2 := c.Sum(nil)
+
+	expected1, err := Sum(first, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(sum1, expected1) {
+		t.Errorf("first Sum result = %x; expected %x", sum1, expected1)
+	}
+
+	expected2, err := Sum(append(append([]byte{}, first...), second...), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(sum2, expected2) {
+		t.Errorf("second Sum result = %x; expected %x", sum2, expected2)
+	}
+}
+
+func TestSumIsRepeatable(t *testing.T) {
+	c, err := NewWithSalt([]byte("9vQe2LmR"))
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("Nothing in life is to be feared, it is only to be understood."))
+
+	sum1 := c.Sum(nil)
+	sum2 := c.Sum(nil)
+	if !bytes.Equal(sum1, sum2) {
+		t.Errorf("repeated Sum results differ: %x and %x", sum1, sum2)
+	}
+}
+
+func TestWriteInChunks(t *testing.T) {
+	salt := []byte("Zp3kW8sN")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1024)
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		c.Write(data[i:end])
+	}
+
+	expected, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("chunked Sum result = %x; expected %x", result, expected)
+	}
+}
+
+func TestReset(t *testing.T) {
+	salt := []byte("Zp3kW8sN")
+	data := []byte("Simplicity is the ultimate sophistication.")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("discarded"))
+	c.Reset()
+	c.Write(data)
+
+	expected, err := Sum(data, salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result after Reset = %x; expected %x", result, expected)
+	}
+}
+
+func TestValidateCandidates(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	stored, err := Sum(password, []byte("u7Yb1xQc"))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+
+	if result, err := Validate(stored, password); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+
+	wrong := []byte("correct horse battery stapler")
+	if result, err := Validate(stored, wrong); err != nil || result {
+		t.Errorf("Validate() = %t, %v; expected false, nil", result, err)
+	}
+}
+
+func TestSaltSize(t *testing.T) {
+	for _, size := range []int{8, 20, 32} {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		if result := c.SaltSize(); result != size {
+			t.Errorf("SaltSize result = %d; expected %d", result, size)
+		}
+		if result := c.Size(); result != sha512.Size384+size {
+			t.Errorf("Size result = %d; expected %d", result, sha512.Size384+size)
+		}
+	}
+}
+
+func TestSalt(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("Be yourself; everyone else is already taken."))
+	expected := c.Sum(nil)
+
+	salt := c.(crypto.Salter).Salt()
+	if len(salt) != DefaultNumSaltBytes {
+		t.Errorf("Salt length = %d; expected %d", len(salt), DefaultNumSaltBytes)
+	}
+	if !bytes.Equal(salt, expected[len(expected)-len(salt):]) {
+		t.Errorf("Salt result = %x; expected suffix of %x", salt, expected)
+	}
+
+	for i := range salt {
+		salt[i] ^= 0xff
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("Sum result after modifying Salt() = %x; expected %x", result, expected)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewWithSalt(nil); !errors.Is(err, ErrNilSalt) {
+		t.Errorf("NewWithSalt(nil) error = %v; expected %v", err, ErrNilSalt)
+	}
+	if _, err := NewWithSalt([]byte{}); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewWithSalt() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := NewForSaltSize(0); !errors.Is(err, ErrSaltTooShort) {
+		t.Errorf("NewForSaltSize() error = %v; expected %v", err, ErrSaltTooShort)
+	}
+	if _, err := Validate(make([]byte, sha512.Size384-1), nil); !errors.Is(err, ErrSliceTooShortSHA384) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSliceTooShortSHA384)
+	}
+	if _, err := Validate(make([]byte, sha512.Size384), nil); !errors.Is(err, ErrSliceTooShortSSHA384) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSliceTooShortSSHA384)
+	}
+}
+
+func TestMaxSaltBytes(t *testing.T) {
+	if _, err := NewWithSalt(make([]byte, MaxSaltBytes)); err != nil {
+		t.Errorf("NewWithSalt() returned unexpected error for %d-byte salt: %e", MaxSaltBytes, err)
+	}
+	if _, err := NewWithSalt(make([]byte, MaxSaltBytes+1)); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewWithSalt() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+	if _, err := NewForSaltSize(MaxSaltBytes); err != nil {
+		t.Errorf("NewForSaltSize() returned unexpected error for %d-byte salt: %e", MaxSaltBytes, err)
+	}
+	if _, err := NewForSaltSize(MaxSaltBytes + 1); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("NewForSaltSize() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+
+	sample := []byte("Well done is better than well said.")
+	stored, err := Sum(sample, make([]byte, MaxSaltBytes))
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result, err := Validate(stored, sample); err != nil || !result {
+		t.Errorf("Validate() = %t, %v; expected true, nil", result, err)
+	}
+	if _, err := Validate(make([]byte, sha512.Size384+MaxSaltBytes+1), sample); !errors.Is(err, ErrSaltTooLong) {
+		t.Errorf("Validate() error = %v; expected %v", err, ErrSaltTooLong)
+	}
+}
+
+func TestURLString(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("All things are strange which are worth knowing."))
+
+	// the sum contains bytes that encode to '+' and '/' in the standard alphabet
+	expectedStd := "{SSHA384}m+8GzqqMDxp+sBMm4JinewRu1YfrcyEgr3pQbC77uRX5tk9aus//Eru8Q0GgIUUrUip3LjVWbW8="
+	expectedURL := "{SSHA384}m-8GzqqMDxp-sBMm4JinewRu1YfrcyEgr3pQbC77uRX5tk9aus__Eru8Q0GgIUUrUip3LjVWbW8="
+
+	if result := c.String(); result != expectedStd {
+		t.Errorf("String result = %s; expected %s", result, expectedStd)
+	}
+	if result := c.(crypto.URLStringer).URLString(); result != expectedURL {
+		t.Errorf("URLString result = %s; expected %s", result, expectedURL)
+	}
+}
+
+func TestValidateAny(t *testing.T) {
+	// salt: "R*w.5Vmo"
+	encoded := "{SSHA384}6MxBeLHC2bHcVYTIHQV7xyG5i5cpXrn2sZ3uihMj0OZoxbJ1PVSmzH2XhbMmhMF7Uip3LjVWbW8="
+
+	if result, err := crypto.ValidateAny(encoded, []byte("You have to be odd to be number one.")); err != nil || !result {
+		t.Errorf("ValidateAny() = %t, %v; expected true, nil", result, err)
+	}
+	if result, err := crypto.ValidateAny(encoded, []byte("You have to be odd to be number two.")); err != nil || result {
+		t.Errorf("ValidateAny() = %t, %v; expected false, nil", result, err)
+	}
+}
+
+func TestClone(t *testing.T) {
+	salt := []byte("fK2o9WbZ")
+	prefix := []byte("Common prefix, ")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write(prefix)
+
+	clone := c.(crypto.Cloner).Clone()
+	c.Write([]byte("first suffix"))
+	clone.Write([]byte("second suffix"))
+
+	expected, err := Sum(append(append([]byte{}, prefix...), "first suffix"...), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := c.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("original Sum result = %x; expected %x", result, expected)
+	}
+
+	expected, err = Sum(append(append([]byte{}, prefix...), "second suffix"...), salt)
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if result := clone.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("clone Sum result = %x; expected %x", result, expected)
+	}
+}
+
+type prefixCase struct {
+	prefix   string
+	expected string
+}
+
+func TestStringWithPrefix(t *testing.T) {
+	c, err := NewWithSalt([]byte("R*w.5Vmo"))
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+
+	c.Write([]byte("You have to be odd to be number one."))
+
+	cases := []prefixCase{
+		{"{ssha384}", "{ssha384}6MxBeLHC2bHcVYTIHQV7xyG5i5cpXrn2sZ3uihMj0OZoxbJ1PVSmzH2XhbMmhMF7Uip3LjVWbW8="},
+		{"SSHA384:", "SSHA384:6MxBeLHC2bHcVYTIHQV7xyG5i5cpXrn2sZ3uihMj0OZoxbJ1PVSmzH2XhbMmhMF7Uip3LjVWbW8="},
+		{"", "6MxBeLHC2bHcVYTIHQV7xyG5i5cpXrn2sZ3uihMj0OZoxbJ1PVSmzH2XhbMmhMF7Uip3LjVWbW8="},
+	}
+
+	for _, tc := range cases {
+		if result := c.(crypto.PrefixStringer).StringWithPrefix(tc.prefix); result != tc.expected {
+			t.Errorf("StringWithPrefix(%q) result = %s; expected %s", tc.prefix, result, tc.expected)
+		}
+	}
+	if result := c.(crypto.PrefixStringer).StringWithPrefix("{SSHA384}"); result != c.String() {
+		t.Errorf("StringWithPrefix(%q) result = %s; expected String() result %s", "{SSHA384}", result, c.String())
+	}
+}
+
+func TestResetWithNewSalt(t *testing.T) {
+	data := []byte("Stay hungry, stay foolish.")
+	salt := []byte("0ldS4lt!")
+
+	c, err := NewWithSalt(salt)
+	if err != nil {
+		t.Errorf("method NewWithSalt() returned unexpected error: %e", err)
+	}
+	c.Write([]byte("discarded"))
+	c.Write(data)
+	before := c.Sum(nil)
+
+	if err := c.(crypto.SaltResetter).ResetWithNewSalt(); err != nil {
+		t.Errorf("method ResetWithNewSalt() returned unexpected error: %e", err)
+	}
+	c.Write(data)
+	after := c.Sum(nil)
+
+	if bytes.Equal(before, after) {
+		t.Errorf("Sum results before and after ResetWithNewSalt are identical: %x", after)
+	}
+	if c.SaltSize() != len(salt) {
+		t.Errorf("SaltSize after ResetWithNewSalt = %d; expected %d", c.SaltSize(), len(salt))
+	}
+	if !bytes.Equal(salt, []byte("0ldS4lt!")) {
+		t.Errorf("ResetWithNewSalt modified the caller's salt slice: %q", salt)
+	}
+
+	expected, err := Sum(data, c.(crypto.Salter).Salt())
+	if err != nil {
+		t.Errorf("method Sum() returned unexpected error: %e", err)
+	}
+	if !bytes.Equal(after, expected) {
+		t.Errorf("Sum result after ResetWithNewSalt = %x; expected %x", after, expected)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	if result := c.Scheme(); result != "{SSHA384}" {
+		t.Errorf("Scheme result = %s; expected %s", result, "{SSHA384}")
+	}
+	if result := c.String(); !strings.HasPrefix(result, c.Scheme()) {
+		t.Errorf("String result %s does not start with Scheme %s", result, c.Scheme())
+	}
+}
+
+func TestConstructorsSetSalt(t *testing.T) {
+	constructors := map[string]func() (crypto.Hash, error){
+		"New":            New,
+		"NewWithSalt":    func() (crypto.Hash, error) { return NewWithSalt([]byte("a")) },
+		"NewForSaltSize": func() (crypto.Hash, error) { return NewForSaltSize(MinSaltBytes) },
+	}
+
+	for name, construct := range constructors {
+		c, err := construct()
+		if err != nil {
+			t.Errorf("method %s() returned unexpected error: %e", name, err)
+			continue
+		}
+		if result := c.SaltSize(); result < MinSaltBytes {
+			t.Errorf("%s() SaltSize result = %d; expected at least %d", name, result, MinSaltBytes)
+		}
+	}
+}
+
+func TestSumSize(t *testing.T) {
+	for _, size := range []int{MinSaltBytes, 8, DefaultNumSaltBytes, 32, MaxSaltBytes} {
+		c, err := NewForSaltSize(size)
+		if err != nil {
+			t.Errorf("method NewForSaltSize() returned unexpected error: %e", err)
+			continue
+		}
+		if result := SumSize(size); result != c.Size() {
+			t.Errorf("SumSize(%d) = %d; expected %d", size, result, c.Size())
+		}
+	}
+}
+
+func TestSharedSaltConstants(t *testing.T) {
+	if DefaultNumSaltBytes != crypto.DefaultNumSaltBytes {
+		t.Errorf("DefaultNumSaltBytes = %d; expected crypto.DefaultNumSaltBytes (%d)", DefaultNumSaltBytes, crypto.DefaultNumSaltBytes)
+	}
+	if MinSaltBytes != crypto.MinSaltBytes {
+		t.Errorf("MinSaltBytes = %d; expected crypto.MinSaltBytes (%d)", MinSaltBytes, crypto.MinSaltBytes)
+	}
+	if MaxSaltBytes != crypto.MaxSaltBytes {
+		t.Errorf("MaxSaltBytes = %d; expected crypto.MaxSaltBytes (%d)", MaxSaltBytes, crypto.MaxSaltBytes)
+	}
+
+	c, err := New()
+	if err != nil {
+		t.Errorf("method New() returned unexpected error: %e", err)
+	}
+	if c.SaltSize() != crypto.DefaultNumSaltBytes {
+		t.Errorf("New() salt size = %d; expected %d", c.SaltSize(), crypto.DefaultNumSaltBytes)
+	}
+}
@@ -0,0 +1,5 @@
+module github.com/kristinjeanna/crypto/ssha512
+
+go 1.18
+
+require github.com/kristinjeanna/crypto v1.1.0